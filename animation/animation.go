@@ -1,10 +1,13 @@
 package animation
 
 import (
+	"errors"
 	"fgengine/types"
 	"fmt"
 )
 
+var ErrMissingAnimation = errors.New("missing animation")
+
 type Animation struct {
 	Name          string      `yaml:"-"`
 	Sprites       []*Sprite   `yaml:"sprites"`
@@ -55,7 +58,20 @@ func (ap *AnimationPlayer) Update(intentAnimation string) {
 		}
 	}
 
+	// a loop on the last frames has nothing after it, the animation ends like above
+	if ap.FrameIndex >= len(ap.ActiveAnimation.FrameData) {
+		ap.FrameIndex = len(ap.ActiveAnimation.FrameData) - 1
+		ap.FrameTimeLeft = 0
+		return
+	}
+
 	ap.FrameTimeLeft = ap.ActiveAnimation.FrameData[ap.FrameIndex].Duration
+	ap.FrameStarted = true
+
+	// a frame without hitboxes ends the active window, the next one is a new attack instance
+	if len(ap.ActiveAnimation.FrameData[ap.FrameIndex].Boxes[types.Hit]) == 0 {
+		ap.AttackConnected = false
+	}
 }
 
 type Sprite struct {
@@ -72,6 +88,14 @@ type AnimationPlayer struct {
 	AnimationQueue  []string              `yaml:"-"` // names are probably smaller than full Animation structs
 
 	FrameTimeLeft int `yaml:"-"`
+	// AttackConnected is set once the current active window landed, so a multi-frame hitbox only connects once
+	AttackConnected bool `yaml:"-"`
+	// ArmorHitsAbsorbed counts the hits taken by armor since the animation started
+	ArmorHitsAbsorbed int `yaml:"-"`
+	// FrameStarted is set when a new frame starts, the game clears it once the frame's events like spawns were triggered
+	FrameStarted bool `yaml:"-"`
+	// AnimationStarted is set by SetAnimation and cleared with FrameStarted, loops going back to the first frame don't set it
+	AnimationStarted bool `yaml:"-"`
 }
 
 func (ap *AnimationPlayer) ActiveSprite() *Sprite {
@@ -88,28 +112,32 @@ func (ap *AnimationPlayer) ActiveSprite() *Sprite {
 	return ap.ActiveAnimation.Sprites[frameData.SpriteIndex]
 }
 
-func (ap *AnimationPlayer) SetAnimation(name string) {
+// SetAnimation starts the animation from its first frame, a missing animation leaves the active one playing.
+func (ap *AnimationPlayer) SetAnimation(name string) error {
 	if name == "" {
-		return
+		return nil
 	}
 	if ap == nil || ap.Animations == nil {
-		fmt.Println("Animation player has no animations map")
-		return
+		return errors.New("animation player has no animations map")
 	}
 
 	anim, exists := ap.Animations[name]
 	if !exists || anim == nil {
-		fmt.Printf("Missing animation %s\n", name)
-		return
+		return fmt.Errorf("%w: %s", ErrMissingAnimation, name)
 	}
 	anim.Name = name
 	ap.ActiveAnimation = anim
 	ap.FrameIndex = 0
+	ap.AttackConnected = false
+	ap.ArmorHitsAbsorbed = 0
+	ap.FrameStarted = true
+	ap.AnimationStarted = true
 	if len(anim.FrameData) == 0 {
 		ap.FrameTimeLeft = 0
-		return
+		return nil
 	}
 	ap.FrameTimeLeft = anim.FrameData[0].Duration
+	return nil
 }
 
 func (ap *AnimationPlayer) ActiveFrameData() *FrameData {
@@ -157,6 +185,15 @@ func (a *Animation) Duration() int {
 	return a.TotalDuration
 }
 
+// MeterCost returns the meter spent when the animation starts, the costs of all its frames together
+func (a *Animation) MeterCost() int {
+	cost := 0
+	for _, frameData := range a.FrameData {
+		cost += frameData.MeterCost
+	}
+	return cost
+}
+
 // Notes for future reference:
 
 /*
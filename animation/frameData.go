@@ -26,21 +26,159 @@ type FrameData struct {
 	//MoveType         MoveType       `yaml:"moveType,omitempty"`
 	//HitType          HitType        `yaml:"hitType,omitempty"`
 	//Phase            AnimationPhase `yaml:"animPhase,omitempty"`
-	Priority  int `yaml:"priority,omitempty"` // maybe used in trades
-	Damage    int `yaml:"damage,omitempty"`
-	Hitstun   int `yaml:"hitstun,omitempty"`
-	Blockstun int `yaml:"blockstun,omitempty"`
-	Pushback  int `yaml:"pushback,omitempty"`
-	Knockback int `yaml:"knockback,omitempty"`
-	Knockup   int `yaml:"knockup,omitempty"`
-
-	CanHardKnockdown bool `yaml:"canHardKnockdown,omitempty"`
+	Priority       int `yaml:"priority,omitempty"` // the higher priority wins trades with the TradePriority rule
+	Damage         int `yaml:"damage,omitempty"`
+	ChipDamage     int `yaml:"chipDamage,omitempty"`     // damage dealt when blocked
+	StarterScaling int `yaml:"starterScaling,omitempty"` // damage percent applied to a whole combo started by this frame, 0 means no scaling
+	Hitstun        int `yaml:"hitstun,omitempty"`
+	Blockstun      int `yaml:"blockstun,omitempty"`
+	Pushback       int `yaml:"pushback,omitempty"`
+	Knockback      int `yaml:"knockback,omitempty"`
+	Knockup        int `yaml:"knockup,omitempty"`
+
+	MeterGainOnHit   int `yaml:"meterGainOnHit,omitempty"`
+	MeterGainOnBlock int `yaml:"meterGainOnBlock,omitempty"`
+	MeterGainOnWhiff int `yaml:"meterGainOnWhiff,omitempty"` // gained when the frame starts, connecting or not
+	MeterCost        int `yaml:"meterCost,omitempty"`        // the costs of all frames are spent once when the animation starts, and required to start it
+
+	Strength AttackStrength `yaml:"strength,omitempty"`
+	Hitstop  int            `yaml:"hitstop,omitempty"` // frames both characters freeze on hit or block, 0 uses the default of the strength
+
+	Guard        GuardType `yaml:"guard,omitempty"`
+	AirBlockable bool      `yaml:"airBlockable,omitempty"` // whether an airborne defender can block it
+
+	// throws, used by frames with Throw boxes
+	ThrowTechWindow int     `yaml:"throwTechWindow,omitempty"` // frames the defender has to tech after the throw connects
+	ThrowOffsetX    float64 `yaml:"throwOffsetX,omitempty"`    // distance in front of the attacker the defender is held at
+	ThrowAnimation  string  `yaml:"throwAnimation,omitempty"`  // attacker animation when the throw connects
+	ThrownAnimation string  `yaml:"thrownAnimation,omitempty"` // defender animation when the throw connects, defaults to "thrown"
+
+	Knockdown        bool `yaml:"knockdown,omitempty"`        // knocks a grounded defender down without a launch, like a sweep
+	CanHardKnockdown bool `yaml:"canHardKnockdown,omitempty"` // the knockdown caused by this hit doesn't allow quick or back rise, implies Knockdown
 	CanWallBounce    bool `yaml:"canWallBounce,omitempty"`
 	CanGroundBounce  bool `yaml:"canGroundBounce,omitempty"`
-	CanOTG           bool `yaml:"canOTG,omitempty"`
-	CommonAudioID    int  `yaml:"soundID,omitempty"` // sound effect ID, 0 means no sound
+	CanOTG           bool `yaml:"canOTG,omitempty"`     // can hit knocked down characters
+	JuggleCost       int  `yaml:"juggleCost,omitempty"` // juggle points spent when hitting an airborne or knocked down defender, 0 means 1
+	CommonAudioID    int  `yaml:"soundID,omitempty"`    // sound effect ID, 0 means no sound
 	UniqueAudioID    int  `yaml:"uniqueSoundID,omitempty"`
 
-	IsInvincible bool `yaml:"isInvincible,omitempty"`
-	HasArmor     bool `yaml:"hasArmor,omitempty"`
+	Spawn      *SpawnData `yaml:"spawn,omitempty"`      // entity created when this frame starts, like a projectile
+	SuperFlash int        `yaml:"superFlash,omitempty"` // frames the opponent is frozen when this frame starts, the attacker keeps moving
+
+	IsInvincible         bool `yaml:"isInvincible,omitempty"` // every attack whiffs
+	StrikeInvincible     bool `yaml:"strikeInvincible,omitempty"`
+	ThrowInvincible      bool `yaml:"throwInvincible,omitempty"`
+	ProjectileInvincible bool `yaml:"projectileInvincible,omitempty"`
+	HasArmor             bool `yaml:"hasArmor,omitempty"`
+	ArmorHits            int  `yaml:"armorHits,omitempty"` // hits absorbed per animation, 0 means 1
+}
+
+// SpawnData describes an entity spawned by a frame, its animation comes from the spawning character
+type SpawnData struct {
+	Animation   string        `yaml:"animation"`
+	Offset      types.Vector2 `yaml:"offset,omitempty"`      // from the character position, mirrored when facing left
+	Velocity    types.Vector2 `yaml:"velocity,omitempty"`    // mirrored when facing left
+	Lifetime    int           `yaml:"lifetime,omitempty"`    // frames before despawning, 0 lasts until it leaves the world or runs out of hits
+	Hits        int           `yaml:"hits,omitempty"`        // hits before despawning, 0 means 1
+	HitInterval int           `yaml:"hitInterval,omitempty"` // frames between two hits of a multi hit entity
+}
+
+// AttackKind separates attacks for invulnerability checks
+type AttackKind uint8
+
+const (
+	AttackStrike AttackKind = iota
+	AttackThrow
+	AttackProjectile
+)
+
+// IsInvulnerableTo returns true if an attack of the given kind whiffs against this frame
+func (fd *FrameData) IsInvulnerableTo(kind AttackKind) bool {
+	if fd.IsInvincible {
+		return true
+	}
+	switch kind {
+	case AttackStrike:
+		return fd.StrikeInvincible
+	case AttackThrow:
+		return fd.ThrowInvincible
+	case AttackProjectile:
+		return fd.ProjectileInvincible
+	default:
+		return false
+	}
+}
+
+// ArmorCapacity returns how many hits the armor of this frame absorbs
+func (fd *FrameData) ArmorCapacity() int {
+	if !fd.HasArmor {
+		return 0
+	}
+	if fd.ArmorHits <= 0 {
+		return 1
+	}
+	return fd.ArmorHits
+}
+
+// AttackStrength is the weight of an attack, it picks defaults like the hitstop
+type AttackStrength uint8
+
+const (
+	StrengthLight AttackStrength = iota
+	StrengthMedium
+	StrengthHeavy
+)
+
+func (s AttackStrength) String() string {
+	switch s {
+	case StrengthLight:
+		return "Light"
+	case StrengthMedium:
+		return "Medium"
+	case StrengthHeavy:
+		return "Heavy"
+	default:
+		return "Unknown"
+	}
+}
+
+// GuardType defines how an attack must be blocked
+type GuardType uint8
+
+const (
+	GuardMid         GuardType = iota // blocked standing or crouching
+	GuardLow                          // must be blocked crouching
+	GuardOverhead                     // must be blocked standing
+	GuardUnblockable                  // can't be blocked
+)
+
+func (g GuardType) String() string {
+	switch g {
+	case GuardMid:
+		return "Mid"
+	case GuardLow:
+		return "Low"
+	case GuardOverhead:
+		return "Overhead"
+	case GuardUnblockable:
+		return "Unblockable"
+	default:
+		return "Unknown"
+	}
+}
+
+// CanBlock returns true if a defender in the given stance is able to block this frame
+func (fd *FrameData) CanBlock(crouching, airborne bool) bool {
+	switch {
+	case fd.Guard == GuardUnblockable:
+		return false
+	case airborne:
+		return fd.AirBlockable
+	case fd.Guard == GuardLow:
+		return crouching
+	case fd.Guard == GuardOverhead:
+		return !crouching
+	default:
+		return true
+	}
 }
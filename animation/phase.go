@@ -0,0 +1,68 @@
+package animation
+
+import "fgengine/types"
+
+// MovePhase is the part of an attack a frame belongs to
+type MovePhase uint8
+
+const (
+	PhaseNone     MovePhase = iota // the animation has no attack boxes
+	PhaseStartup                   // before an active frame
+	PhaseActive                    // has hit or throw boxes
+	PhaseRecovery                  // after the last active frame, or flagged with IsRecovery
+)
+
+func (p MovePhase) String() string {
+	switch p {
+	case PhaseNone:
+		return "None"
+	case PhaseStartup:
+		return "Startup"
+	case PhaseActive:
+		return "Active"
+	case PhaseRecovery:
+		return "Recovery"
+	default:
+		return "Unknown"
+	}
+}
+
+// isActiveFrame returns true if the frame can hit or grab
+func (fd *FrameData) isActiveFrame() bool {
+	return len(fd.Boxes[types.Hit]) > 0 || len(fd.Boxes[types.Throw]) > 0
+}
+
+// Phase classifies a frame by where the animation has hit boxes, frames between two active windows count as startup of the next one
+func (a *Animation) Phase(frameIndex int) MovePhase {
+	if frameIndex < 0 || frameIndex >= len(a.FrameData) {
+		return PhaseNone
+	}
+
+	frameData := &a.FrameData[frameIndex]
+	if frameData.IsRecovery {
+		return PhaseRecovery
+	}
+	if frameData.isActiveFrame() {
+		return PhaseActive
+	}
+
+	for i := frameIndex + 1; i < len(a.FrameData); i++ {
+		if a.FrameData[i].isActiveFrame() {
+			return PhaseStartup
+		}
+	}
+	for i := range frameIndex {
+		if a.FrameData[i].isActiveFrame() {
+			return PhaseRecovery
+		}
+	}
+	return PhaseNone
+}
+
+// ActivePhase returns the move phase of the frame being played
+func (ap *AnimationPlayer) ActivePhase() MovePhase {
+	if ap == nil || ap.ActiveAnimation == nil {
+		return PhaseNone
+	}
+	return ap.ActiveAnimation.Phase(ap.FrameIndex)
+}
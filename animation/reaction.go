@@ -0,0 +1,90 @@
+package animation
+
+// Reaction is what the character is doing after being hit, while it isn't ReactionNone the player has no control
+type Reaction uint8
+
+const (
+	ReactionNone Reaction = iota
+	ReactionHurt
+	ReactionBlock
+	ReactionKnockdown
+	ReactionWakeup
+	ReactionThrown
+)
+
+func (r Reaction) String() string {
+	switch r {
+	case ReactionNone:
+		return "None"
+	case ReactionHurt:
+		return "Hurt"
+	case ReactionBlock:
+		return "Block"
+	case ReactionKnockdown:
+		return "Knockdown"
+	case ReactionWakeup:
+		return "Wakeup"
+	case ReactionThrown:
+		return "Thrown"
+	default:
+		return "Unknown"
+	}
+}
+
+// Conventional reaction animation names, characters are not required to have all of them
+const (
+	AnimHurtHigh  = "hurt_high"
+	AnimHurtLow   = "hurt_low"
+	AnimHurtAir   = "hurt_air"
+	AnimKnockdown = "knockdown"
+	AnimWakeup    = "wakeup"
+	AnimBlockHigh = "block_high"
+	AnimBlockLow  = "block_low"
+	AnimBlockAir  = "block_air"
+	AnimThrown    = "thrown"
+	AnimThrowTech = "throw_tech"
+	AnimAirTech   = "air_tech"
+	AnimQuickRise = "quick_rise"
+	AnimBackRise  = "back_rise"
+)
+
+// WakeupOption is how a character gets up from a soft knockdown
+type WakeupOption uint8
+
+const (
+	WakeupNormal    WakeupOption = iota
+	WakeupQuickRise              // gets up early in place
+	WakeupBackRise               // gets up early rolling away from the opponent
+)
+
+func (w WakeupOption) String() string {
+	switch w {
+	case WakeupNormal:
+		return "Normal"
+	case WakeupQuickRise:
+		return "QuickRise"
+	case WakeupBackRise:
+		return "BackRise"
+	default:
+		return "Unknown"
+	}
+}
+
+// SetFirstAvailableAnimation plays the first animation in names that the character has, returns false if none exists
+func (ap *AnimationPlayer) SetFirstAvailableAnimation(names ...string) bool {
+	if ap == nil || ap.Animations == nil {
+		return false
+	}
+	for _, name := range names {
+		if anim, exists := ap.Animations[name]; exists && anim != nil {
+			ap.SetAnimation(name)
+			return true
+		}
+	}
+	return false
+}
+
+// IsActionable returns true when the character isn't locked by stun or any reaction
+func (sm *StateMachine) IsActionable() bool {
+	return sm.Reaction == ReactionNone && sm.Hitstun == 0 && sm.Blockstun == 0
+}
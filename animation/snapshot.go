@@ -0,0 +1,68 @@
+package animation
+
+import "slices"
+
+// FrameRef points to a frame by animation name, unlike a *FrameData it stays valid when copied to another game state
+type FrameRef struct {
+	Animation string
+	Index     int
+}
+
+// PlayerState is the part of an AnimationPlayer that changes during a match, the animations themselves are left out.
+type PlayerState struct {
+	Animation         string // "" when no animation is active
+	FrameIndex        int
+	FrameTimeLeft     int
+	Queue             []string
+	AttackConnected   bool
+	ArmorHitsAbsorbed int
+	FrameStarted      bool
+	AnimationStarted  bool
+}
+
+// SaveState copies the playback state, nothing in it is shared with the player
+func (ap *AnimationPlayer) SaveState() PlayerState {
+	state := PlayerState{
+		FrameIndex:        ap.FrameIndex,
+		FrameTimeLeft:     ap.FrameTimeLeft,
+		Queue:             slices.Clone(ap.AnimationQueue),
+		AttackConnected:   ap.AttackConnected,
+		ArmorHitsAbsorbed: ap.ArmorHitsAbsorbed,
+		FrameStarted:      ap.FrameStarted,
+		AnimationStarted:  ap.AnimationStarted,
+	}
+	if ap.ActiveAnimation != nil {
+		state.Animation = ap.ActiveAnimationName()
+	}
+	return state
+}
+
+// LoadState restores a state saved by SaveState, the animation is looked up by name in the player's animations.
+func (ap *AnimationPlayer) LoadState(state PlayerState) {
+	ap.ActiveAnimation = nil
+	if anim, ok := ap.Animations[state.Animation]; ok {
+		anim.Name = state.Animation
+		ap.ActiveAnimation = anim
+	}
+	ap.FrameIndex = state.FrameIndex
+	ap.FrameTimeLeft = state.FrameTimeLeft
+	ap.AnimationQueue = slices.Clone(state.Queue)
+	ap.AttackConnected = state.AttackConnected
+	ap.ArmorHitsAbsorbed = state.ArmorHitsAbsorbed
+	ap.FrameStarted = state.FrameStarted
+	ap.AnimationStarted = state.AnimationStarted
+}
+
+// ActiveFrameRef returns a reference to the current frame
+func (ap *AnimationPlayer) ActiveFrameRef() FrameRef {
+	return FrameRef{Animation: ap.ActiveAnimationName(), Index: ap.FrameIndex}
+}
+
+// FrameDataAt returns the frame a reference points to, nil if the player doesn't have it
+func (ap *AnimationPlayer) FrameDataAt(ref FrameRef) *FrameData {
+	anim, ok := ap.Animations[ref.Animation]
+	if !ok || ref.Index < 0 || ref.Index >= len(anim.FrameData) {
+		return nil
+	}
+	return &anim.FrameData[ref.Index]
+}
@@ -3,25 +3,38 @@ package animation
 import (
 	"fgengine/constants"
 	"fgengine/types"
-	"math"
 )
 
 const (
-	horizontalFriction  = 0.80
-	minHorizontalSpeed  = 0.05
-	maxHorizontalSpeedX = 999
+	horizontalFriction  = types.FixedOne * 4 / 5 // 0.8
+	minHorizontalSpeed  = types.FixedOne / 20    // 0.05
+	maxHorizontalSpeedX = types.FixedOne * 999
 
-	maxVerticalSpeedY = 10.0
+	maxVerticalSpeedY = types.FixedOne * 10
 )
 
 type StateMachine struct {
 	//ActiveState         State
 	//PreviousState       State
-	HP                  int           `yaml:"-"`
-	Position            types.Vector2 `yaml:"-"`
-	Velocity            types.Vector2 `yaml:"-"`
-	IgnoreGravityFrames int           `yaml:"-"`
-	IsFacingLeft        Orientation   `yaml:"-"`
+	HP                  int                `yaml:"-"`
+	Position            types.FixedVector2 `yaml:"-"`
+	Velocity            types.FixedVector2 `yaml:"-"`
+	IgnoreGravityFrames int                `yaml:"-"`
+	Hitstun             int                `yaml:"-"` // frames left in hitstun
+	Blockstun           int                `yaml:"-"` // frames left in blockstun
+	KnockdownTimer      int                `yaml:"-"` // frames left on the ground before waking up
+	Reaction            Reaction           `yaml:"-"`
+	Crouching           bool               `yaml:"-"` // holding down on the ground the last time the player could act
+	ProximityGuard      bool               `yaml:"-"` // holding back near an active hitbox, shows the block pose but can still act
+	ThrowInvulnerable   int                `yaml:"-"` // frames left where throws whiff, given after stun and on wakeup
+	FlashFrames         int                `yaml:"-"` // frames left of the armor hit flash
+	Hitstop             int                `yaml:"-"` // frames left frozen, animation, physics and stun timers don't advance
+	ClashCancelFrames   int                `yaml:"-"` // frames left where any move can be canceled into after a clash
+	GroundBounce        bool               `yaml:"-"` // the juggle will bounce the next time the character lands
+	WallBounce          bool               `yaml:"-"` // the juggle will bounce the next time the character touches a wall
+	HardKnockdown       bool               `yaml:"-"` // the next knockdown is hard, fixed wakeup time without rise options
+	WakeupOption        WakeupOption       `yaml:"-"` // rise chosen during the current soft knockdown
+	IsFacingLeft        Orientation        `yaml:"-"`
 
 	AnimPlayer *AnimationPlayer `yaml:"activeAnim"`
 }
@@ -34,18 +47,18 @@ const (
 )
 
 func (sm *StateMachine) IsAirborne() bool {
-	return sm.Position.Y < constants.GroundLevelY
+	return sm.Position.Y < constants.FixedGroundLevelY
 }
 
 // ApplyVelocity applies movement deltas from the current frame data.
 func (sm *StateMachine) ApplyVelocity() {
 	frameData := sm.AnimPlayer.ActiveFrameData()
-	incVelX := frameData.IncVelocityX
+	incVelX := types.FloatToFixed(frameData.IncVelocityX)
 	if sm.IsFacingLeft {
 		incVelX = -incVelX
 	}
 	sm.Velocity.X += incVelX
-	sm.Velocity.Y += frameData.IncVelocityY
+	sm.Velocity.Y += types.FloatToFixed(frameData.IncVelocityY)
 
 }
 
@@ -57,15 +70,15 @@ func (sm *StateMachine) ApplyPhysics() {
 			sm.Velocity.X = -maxHorizontalSpeedX
 		}
 
-		sm.Velocity.X *= horizontalFriction
-		if math.Abs(sm.Velocity.X) < minHorizontalSpeed {
+		sm.Velocity.X = sm.Velocity.X.Mul(horizontalFriction)
+		if sm.Velocity.X.Abs() < minHorizontalSpeed {
 			sm.Velocity.X = 0
 		}
 	}
 	// Apply simple gravity while in the air.
 	if sm.IgnoreGravityFrames > 0 {
 		sm.IgnoreGravityFrames--
-	} else if sm.Position.Y < constants.GroundLevelY || sm.Velocity.Y < 0 {
+	} else if sm.Position.Y < constants.FixedGroundLevelY || sm.Velocity.Y < 0 {
 		sm.Velocity.Y += constants.Gravity
 		if sm.Velocity.Y > maxVerticalSpeedY {
 			sm.Velocity.Y = maxVerticalSpeedY
@@ -80,8 +93,8 @@ func (sm *StateMachine) ApplyPhysics() {
 	if sm.Position.X < 0 {
 		sm.Position.X = 0
 		sm.Velocity.X = 0
-	} else if sm.Position.X > constants.WorldWidth {
-		sm.Position.X = constants.WorldWidth
+	} else if sm.Position.X > constants.FixedWorldWidth {
+		sm.Position.X = constants.FixedWorldWidth
 		sm.Velocity.X = 0
 	}
 
@@ -90,8 +103,8 @@ func (sm *StateMachine) ApplyPhysics() {
 		if sm.Velocity.Y < 0 {
 			sm.Velocity.Y = 0
 		}
-	} else if sm.Position.Y > constants.GroundLevelY {
-		sm.Position.Y = constants.GroundLevelY
+	} else if sm.Position.Y > constants.FixedGroundLevelY {
+		sm.Position.Y = constants.FixedGroundLevelY
 		sm.Velocity.Y = 0
 	}
 }
@@ -3,18 +3,65 @@ package character
 import (
 	"fgengine/animation"
 	"fgengine/constants"
+	"fgengine/input"
 	"fgengine/types"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Character struct {
 	Name         string                  `yaml:"name"`
+	Health       int                     `yaml:"health,omitempty"` // 0 uses defaultHealth
+	Meter        MeterConfig             `yaml:"meter,omitempty"`
 	StateMachine *animation.StateMachine `yaml:"stateMachine"`
+	// Commands are the motion inputs of the character, checked in order so the first one that matches wins
+	Commands []input.Command `yaml:"commands,omitempty"`
+
+	// FilePath and DataChecksum identify the file the character was loaded from, replays use them to find it again
+	FilePath     string `yaml:"-"`
+	DataChecksum uint64 `yaml:"-"`
+}
+
+// MeterConfig holds the character wide meter values, per move gains and costs are in the frame data
+type MeterConfig struct {
+	Max                int `yaml:"max,omitempty"`                // 0 uses defaultMaxMeter
+	DamageTakenPercent int `yaml:"damageTakenPercent,omitempty"` // percent of the damage taken gained as meter
+}
+
+const (
+	defaultHealth   = 10000
+	defaultMaxMeter = 3000 // three bars of 1000
+)
+
+// MaxHealth returns the health the character starts every round with
+func (c *Character) MaxHealth() int {
+	if c.Health <= 0 {
+		return defaultHealth
+	}
+	return c.Health
+}
+
+// InputHistoryLength returns the frames of input kept for the character, enough for its longest command
+func (c *Character) InputHistoryLength() int {
+	length := constants.MinInputHistory
+	for i := range c.Commands {
+		length = max(length, c.Commands[i].HistoryLength())
+	}
+	return length
+}
+
+// MaxMeter returns the meter cap of the character
+func (c *Character) MaxMeter() int {
+	if c.Meter.Max <= 0 {
+		return defaultMaxMeter
+	}
+	return c.Meter.Max
 }
 
 func LoadCharacter(name string, playerSide int) (*Character, error) {
@@ -22,19 +69,41 @@ func LoadCharacter(name string, playerSide int) (*Character, error) {
 	if err != nil {
 		return nil, err
 	}
-	chara.initialize(playerSide)
+	if err := chara.initialize(playerSide); err != nil {
+		return nil, err
+	}
+	return chara, nil
+}
+
+// LoadCharacterFile loads a character from a file outside the assets, like the ones of the tests
+func LoadCharacterFile(filePath string, playerSide int) (*Character, error) {
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	chara, err := loadCharacterFile(filePath, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := chara.initialize(playerSide); err != nil {
+		return nil, err
+	}
 	return chara, nil
 }
 
 func loadCharacterByName(name string) (*Character, error) {
-	filePath := "./assets/characters/" + name + ".yaml"
+	return loadCharacterFile("./assets/characters/"+name+".yaml", name)
+}
+
+func loadCharacterFile(filePath, name string) (*Character, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read character file: %w", err)
 	}
 
+	checksum := fnv.New64a()
+	checksum.Write(data)
 	character := &Character{
-		Name: name,
+		Name:         name,
+		FilePath:     filePath,
+		DataChecksum: checksum.Sum64(),
 	}
 	if err := yaml.Unmarshal(data, character); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal character data: %w", err)
@@ -60,46 +129,61 @@ func loadCharacterByName(name string) (*Character, error) {
 			}
 		}
 	}
+
+	for i := range character.Commands {
+		command := &character.Commands[i]
+		if err := command.Parse(); err != nil {
+			return nil, err
+		}
+		if _, ok := character.StateMachine.AnimPlayer.Animations[command.Animation]; !ok {
+			return nil, fmt.Errorf("command %q uses the missing animation %q", command.Input, command.Animation)
+		}
+	}
 	return character, nil
 }
 
-func (c *Character) initialize(playerSide int) {
+func (c *Character) initialize(playerSide int) error {
 	if c.StateMachine == nil {
 		c.StateMachine = new(animation.StateMachine{})
 	}
 	if c.StateMachine.AnimPlayer == nil {
 		c.StateMachine.AnimPlayer = new(animation.AnimationPlayer{})
 	}
+	return c.Reset(playerSide)
+}
 
-	var initialX float64
+// Reset puts the character back at the starting position of its side with full health, every gameplay state is cleared.
+// It fails when the initial animation can't be set, which loading the character already reports.
+func (c *Character) Reset(playerSide int) error {
+	player := c.StateMachine.AnimPlayer
+	*c.StateMachine = animation.StateMachine{AnimPlayer: player}
+
+	var initialX types.Fixed
 	var facing animation.Orientation
 	switch playerSide {
 	case 1:
-		initialX = constants.WorldWidth / 4
+		initialX = constants.FixedWorldWidth / 4
 		facing = animation.Right
 	case 2:
-		initialX = 3 * constants.WorldWidth / 4
+		initialX = 3 * constants.FixedWorldWidth / 4
 		facing = animation.Left
 	}
 
-	c.StateMachine.HP = 10000
-	c.StateMachine.Position = types.Vector2{X: initialX, Y: constants.WorldHeight / 2}
+	c.StateMachine.HP = c.MaxHealth()
+	c.StateMachine.Position = types.FixedVector2{X: initialX, Y: constants.FixedWorldHeight / 2}
 	c.StateMachine.IsFacingLeft = facing
-	c.StateMachine.Velocity = types.Vector2{}
-	c.StateMachine.IgnoreGravityFrames = 0
-
-	setInitialAnimation(c.StateMachine.AnimPlayer)
 
+	player.ActiveAnimation = nil
+	return setInitialAnimation(player)
 }
 
-func setInitialAnimation(player *animation.AnimationPlayer) {
+func setInitialAnimation(player *animation.AnimationPlayer) error {
 	if player == nil || len(player.Animations) == 0 || player.ActiveAnimation != nil {
-		return
+		return nil
 	}
 
 	if _, ok := player.Animations["idle"]; ok {
-		player.SetAnimation("idle")
-		return
+		return player.SetAnimation("idle")
 	}
 
 	animNames := make([]string, 0, len(player.Animations))
@@ -107,7 +191,7 @@ func setInitialAnimation(player *animation.AnimationPlayer) {
 		animNames = append(animNames, name)
 	}
 	slices.Sort(animNames)
-	player.SetAnimation(animNames[0])
+	return player.SetAnimation(animNames[0])
 }
 
 // resolveRelativePath converts a relative path to an absolute path based on a reference path
@@ -119,8 +203,9 @@ func resolveRelativePath(relativePath, referencePath string) string {
 	return filepath.Clean(filepath.Join(referenceDir, relativePath))
 }
 
+// Position returns the world position for rendering, the simulation uses StateMachine.Position
 func (c *Character) Position() types.Vector2 {
-	return c.StateMachine.Position
+	return c.StateMachine.Position.Float()
 }
 
 func (c *Character) Sprite() *animation.Sprite {
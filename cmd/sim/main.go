@@ -0,0 +1,151 @@
+// Command sim runs a match without a window and prints it as JSON, for tests and debugging.
+//
+//	go run ./cmd/sim -inputs script.txt -output trace
+//	go run ./cmd/sim -replay replays/20260101-120000.fgr -output checksums
+//
+// Run it from the repository root, character files are found relative to it.
+package main
+
+import (
+	"encoding/json"
+	"fgengine/character"
+	"fgengine/gameplay"
+	"fgengine/input"
+	"fgengine/replay"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+type checksumFrame struct {
+	Frame    int    `json:"frame"`
+	Checksum string `json:"checksum"`
+}
+
+type finalState struct {
+	Frames   int                 `json:"frames"`
+	Checksum string              `json:"checksum"`
+	State    gameplay.FrameTrace `json:"state"`
+	Match    matchState          `json:"match"`
+}
+
+type matchState struct {
+	Round  int    `json:"round"`
+	Timer  int    `json:"timer"`
+	Wins   [2]int `json:"wins"`
+	Over   bool   `json:"over"`
+	Winner int    `json:"winner"` // -1 on a draw or before the match is over
+}
+
+func main() {
+	inputsPath := flag.String("inputs", "", "input script to run, see input.ReadScript")
+	replayPath := flag.String("replay", "", "replay file to run instead of an input script")
+	playerOne := flag.String("p1", "PlaceHolder", "character of player one for input scripts")
+	playerTwo := flag.String("p2", "PlaceHolder", "character of player two for input scripts")
+	rulesPath := flag.String("rules", gameplay.DefaultRulesPath, "rules file for input scripts, replays keep their own rules")
+	extraFrames := flag.Int("frames", 0, "frames to run without inputs after the script or replay")
+	output := flag.String("output", "final", "what to print: final, trace or checksums")
+	flag.Parse()
+
+	game, frames, err := load(*inputsPath, *replayPath, *playerOne, *playerTwo, *rulesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for range max(*extraFrames, 0) {
+		frames = append(frames, [2]input.GameInput{})
+	}
+
+	var result any
+	switch *output {
+	case "final":
+		for _, inputs := range frames {
+			game.Update(inputs)
+		}
+		match := game.Match
+		state := finalState{
+			Frames: len(frames),
+			State:  game.Trace(),
+			Match:  matchState{Round: match.Round, Timer: match.Timer, Wins: match.Wins, Over: match.Over(), Winner: match.Winner},
+		}
+		if state.Checksum, err = checksum(game); err != nil {
+			log.Fatal(err)
+		}
+		result = state
+	case "trace":
+		traces := make([]gameplay.FrameTrace, 0, len(frames))
+		for _, inputs := range frames {
+			game.Update(inputs)
+			traces = append(traces, game.Trace())
+		}
+		result = traces
+	case "checksums":
+		checksums := make([]checksumFrame, 0, len(frames))
+		for _, inputs := range frames {
+			game.Update(inputs)
+			sum, err := checksum(game)
+			if err != nil {
+				log.Fatal(err)
+			}
+			checksums = append(checksums, checksumFrame{Frame: game.Frame, Checksum: sum})
+		}
+		result = checksums
+	default:
+		log.Fatalf("invalid output %q, must be final, trace or checksums", *output)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load returns the game at its first frame and the inputs of every frame to run
+func load(inputsPath, replayPath, playerOne, playerTwo, rulesPath string) (*gameplay.GameState, [][2]input.GameInput, error) {
+	switch {
+	case inputsPath != "" && replayPath != "":
+		return nil, nil, fmt.Errorf("-inputs and -replay can't be used together")
+	case replayPath != "":
+		r, err := replay.Load(replayPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		game, err := r.NewGame()
+		return game, r.Inputs, err
+	case inputsPath != "":
+		file, err := os.Open(inputsPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		frames, err := input.ReadScript(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", inputsPath, err)
+		}
+
+		var characters [2]*character.Character
+		for i, name := range [2]string{playerOne, playerTwo} {
+			if characters[i], err = character.LoadCharacter(name, i+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		rules, err := gameplay.LoadRules(rulesPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &gameplay.GameState{Characters: characters, Rules: rules}, frames, nil
+	default:
+		return nil, nil, fmt.Errorf("an input script or a replay is needed, see -inputs and -replay")
+	}
+}
+
+// checksum is printed in hex, JSON numbers can't hold every uint64
+func checksum(game *gameplay.GameState) (string, error) {
+	snapshot := game.Save()
+	sum, err := snapshot.Checksum()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x", sum), nil
+}
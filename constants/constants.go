@@ -12,12 +12,20 @@ const (
 	CameraWidth  float64 = 640
 	CameraHeight float64 = 360
 
-	Gravity         float64 = 1
-	MaxInputHistory int     = 30
+	Gravity         types.Fixed = types.FixedOne
+	MinInputHistory int         = 30 // frames of input kept for every character, commands needing more raise it
 
 	GroundLevelY float64 = WorldHeight - 50
+
+	// the simulation works in fixed point, the float values above are for rendering
+	FixedWorldWidth   = types.FixedOne * types.Fixed(WorldWidth)
+	FixedWorldHeight  = types.FixedOne * types.Fixed(WorldHeight)
+	FixedGroundLevelY = types.FixedOne * types.Fixed(GroundLevelY)
 )
 
+// EngineVersion is saved in replays, change it when the simulation changes so old replays would play differently
+const EngineVersion = "0.1.0"
+
 const (
 	LayerBG = iota
 	LayerHUD
@@ -30,6 +38,7 @@ const LayerCount = 4
 var StageColor = color.RGBA{R: 100, G: 149, B: 237, A: 255} // Cornflower Blue
 
 var World = types.Rect{X: 0, Y: 0, W: WorldWidth, H: WorldHeight} // camera * 1.2
+var FixedWorld = types.FixedRect{X: 0, Y: 0, W: FixedWorldWidth, H: FixedWorldHeight}
 var Camera = types.Rect{X: 0, Y: 0, W: CameraWidth, H: CameraHeight}
 
 type Scene int
@@ -1,7 +1,8 @@
-package input
+package controller
 
 import (
 	"fgengine/config"
+	"fgengine/input"
 	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -17,14 +18,14 @@ const (
 
 type Input struct {
 	Owner         ControllerPosition
-	ActiveButtons GameInput
-	PrevButtons   GameInput
+	ActiveButtons input.GameInput
+	PrevButtons   input.GameInput
 	ID            ebiten.GamepadID
 	Mapping       InputMap
 }
 
-func GetPlayerInputs() [2]GameInput {
-	inputs := [2]GameInput{NoInput, NoInput}
+func GetPlayerInputs() [2]input.GameInput {
+	inputs := [2]input.GameInput{input.NoInput, input.NoInput}
 	for _, inpu := range GlobalInputs {
 		if inpu.Owner == P1Side {
 			inputs[0] |= inpu.ActiveButtons
@@ -37,8 +38,8 @@ func GetPlayerInputs() [2]GameInput {
 }
 
 // PollGamepads returns the combined GameInput for the specified gamepad IDs and the keyboard(if ID is -1). If no IDs are provided(nil is passed), it checks all connected gamepads.
-func PollGamepads(ids []ebiten.GamepadID) GameInput {
-	var localInputs GameInput
+func PollGamepads(ids []ebiten.GamepadID) input.GameInput {
+	var localInputs input.GameInput
 	inputmap := NewDefaultInputMap()
 
 	// If nil is passed, check all connected gamepads
@@ -77,20 +78,30 @@ func PollGamepads(ids []ebiten.GamepadID) GameInput {
 			// Left stick X axis (axis 0)
 			xValue := ebiten.GamepadAxisValue(gamepadID, 0)
 			if xValue > config.ActiveConfig.ControllerDeadzone {
-				localInputs |= Right
+				localInputs |= input.Right
 			} else if xValue < -config.ActiveConfig.ControllerDeadzone {
-				localInputs |= Left
+				localInputs |= input.Left
 			}
 
 			// Left stick Y axis (axis 1)
 			yValue := ebiten.GamepadAxisValue(gamepadID, 1)
 			if yValue > config.ActiveConfig.ControllerDeadzone {
-				localInputs |= Down
+				localInputs |= input.Down
 			} else if yValue < -config.ActiveConfig.ControllerDeadzone {
-				localInputs |= Up
+				localInputs |= input.Up
 			}
 		}
 	}
 	checkSOCD(&localInputs)
 	return localInputs
 }
+
+func checkSOCD(in *input.GameInput) {
+	if in.IsPressed(input.Left) && in.IsPressed(input.Right) {
+		*in &^= (input.Left | input.Right)
+	}
+
+	if in.IsPressed(input.Up) && in.IsPressed(input.Down) {
+		*in &^= (input.Up | input.Down)
+	}
+}
@@ -73,7 +73,10 @@ func (ed *CharacterEditor) setActiveAnimation(name string) {
 	if p == nil {
 		return
 	}
-	p.SetAnimation(name)
+	if err := p.SetAnimation(name); err != nil {
+		ed.statusLine = err.Error()
+		return
+	}
 	ed.activeAnimationName = name
 	if ed.char != nil {
 		ed.renameCharacterTo = ed.char.Name
@@ -165,10 +168,13 @@ func (ed *CharacterEditor) renameActiveAnimation(newName string) {
 	delete(p.Animations, oldName)
 	p.Animations[newName] = anim
 	anim.Name = newName
-	p.SetAnimation(newName)
+	ed.markDirty()
+	if err := p.SetAnimation(newName); err != nil {
+		ed.statusLine = err.Error()
+		return
+	}
 	ed.activeAnimationName = newName
 	ed.statusLine = "Animation renamed"
-	ed.markDirty()
 }
 
 func (ed *CharacterEditor) deleteActiveAnimation() {
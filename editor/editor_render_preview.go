@@ -17,6 +17,8 @@ var (
 		types.Collision: {R: 80, G: 80, B: 80, A: 32},
 		types.Hit:       {R: 100, G: 40, B: 40, A: 32},
 		types.Hurt:      {R: 40, G: 100, B: 40, A: 32},
+		types.Throw:     {R: 100, G: 40, B: 100, A: 32},
+		types.Throwable: {R: 40, G: 40, B: 100, A: 32},
 	}
 )
 
@@ -102,6 +102,24 @@ func (ed *CharacterEditor) drawCharacterWindow() {
 		ed.createEmptyAnimation(ed.newAnimationName)
 	}
 
+	health := int32(ed.char.Health)
+	if imgui.InputInt("Health", &health) {
+		ed.char.Health = max(int(health), 0)
+		ed.markDirty()
+	}
+
+	imgui.SeparatorText("Meter")
+	maxMeter := int32(ed.char.Meter.Max)
+	if imgui.InputInt("Max Meter", &maxMeter) {
+		ed.char.Meter.Max = max(int(maxMeter), 0)
+		ed.markDirty()
+	}
+	damageTakenPercent := int32(ed.char.Meter.DamageTakenPercent)
+	if imgui.InputInt("Damage Taken Meter %", &damageTakenPercent) {
+		ed.char.Meter.DamageTakenPercent = max(int(damageTakenPercent), 0)
+		ed.markDirty()
+	}
+
 	imgui.Separator()
 	imgui.Text("Edit names and delete animation from the top menu")
 	imgui.Text(fmt.Sprintf("Current Animation: %s", ed.activeAnimationName))
@@ -243,6 +261,19 @@ func (ed *CharacterEditor) drawFrameDataWindow() {
 		fd.Damage = int(damage)
 		ed.markDirty()
 	}
+	chipDamage := int32(fd.ChipDamage)
+	if imgui.InputInt("Chip Damage", &chipDamage) {
+		fd.ChipDamage = int(chipDamage)
+		ed.markDirty()
+	}
+	starterScaling := int32(fd.StarterScaling)
+	if imgui.InputInt("Starter Scaling %", &starterScaling) {
+		if starterScaling < 0 {
+			starterScaling = 0
+		}
+		fd.StarterScaling = int(starterScaling)
+		ed.markDirty()
+	}
 	hitstun := int32(fd.Hitstun)
 	if imgui.InputInt("Hitstun", &hitstun) {
 		fd.Hitstun = int(hitstun)
@@ -269,6 +300,94 @@ func (ed *CharacterEditor) drawFrameDataWindow() {
 		ed.markDirty()
 	}
 
+	imgui.SeparatorText("Meter")
+	meterGainOnHit := int32(fd.MeterGainOnHit)
+	if imgui.InputInt("Meter Gain On Hit", &meterGainOnHit) {
+		fd.MeterGainOnHit = max(int(meterGainOnHit), 0)
+		ed.markDirty()
+	}
+	meterGainOnBlock := int32(fd.MeterGainOnBlock)
+	if imgui.InputInt("Meter Gain On Block", &meterGainOnBlock) {
+		fd.MeterGainOnBlock = max(int(meterGainOnBlock), 0)
+		ed.markDirty()
+	}
+	meterGainOnWhiff := int32(fd.MeterGainOnWhiff)
+	if imgui.InputInt("Meter Gain On Whiff", &meterGainOnWhiff) {
+		fd.MeterGainOnWhiff = max(int(meterGainOnWhiff), 0)
+		ed.markDirty()
+	}
+	meterCost := int32(fd.MeterCost)
+	if imgui.InputInt("Meter Cost", &meterCost) {
+		fd.MeterCost = max(int(meterCost), 0)
+		ed.markDirty()
+	}
+
+	strengthNames := []string{"Light", "Medium", "Heavy"}
+	strength := int32(fd.Strength)
+	if imgui.ComboStrarrV("Strength", &strength, strengthNames, int32(len(strengthNames)), -1) {
+		fd.Strength = animation.AttackStrength(strength)
+		ed.markDirty()
+	}
+	hitstop := int32(fd.Hitstop)
+	if imgui.InputInt("Hitstop", &hitstop) {
+		if hitstop < 0 {
+			hitstop = 0
+		}
+		fd.Hitstop = int(hitstop)
+		ed.markDirty()
+	}
+
+	guardNames := []string{"Mid", "Low", "Overhead", "Unblockable"}
+	guard := int32(fd.Guard)
+	if imgui.ComboStrarrV("Guard", &guard, guardNames, int32(len(guardNames)), -1) {
+		fd.Guard = animation.GuardType(guard)
+		ed.markDirty()
+	}
+	if imgui.Checkbox("Air Blockable", &fd.AirBlockable) {
+		ed.markDirty()
+	}
+
+	imgui.SeparatorText("Throw")
+	techWindow := int32(fd.ThrowTechWindow)
+	if imgui.InputInt("Throw Tech Window", &techWindow) {
+		if techWindow < 0 {
+			techWindow = 0
+		}
+		fd.ThrowTechWindow = int(techWindow)
+		ed.markDirty()
+	}
+	throwOffset := float32(fd.ThrowOffsetX)
+	if imgui.InputFloat("Throw Offset X", &throwOffset) {
+		fd.ThrowOffsetX = float64(throwOffset)
+		ed.markDirty()
+	}
+	if imgui.InputTextWithHint("Throw Animation", "optional", &fd.ThrowAnimation, 0, nil) {
+		ed.markDirty()
+	}
+	if imgui.InputTextWithHint("Thrown Animation", "thrown", &fd.ThrownAnimation, 0, nil) {
+		ed.markDirty()
+	}
+
+	superFlash := int32(fd.SuperFlash)
+	if imgui.InputInt("Super Flash", &superFlash) {
+		fd.SuperFlash = max(int(superFlash), 0)
+		ed.markDirty()
+	}
+
+	imgui.SeparatorText("Spawn")
+	spawns := fd.Spawn != nil
+	if imgui.Checkbox("Spawns Entity", &spawns) {
+		if spawns {
+			fd.Spawn = new(animation.SpawnData{})
+		} else {
+			fd.Spawn = nil
+		}
+		ed.markDirty()
+	}
+	if fd.Spawn != nil {
+		ed.drawSpawnDataEditor(fd.Spawn)
+	}
+
 	imgui.SeparatorText("Current Frame Sprite Anchor")
 	anim := ed.activeAnimation()
 	if anim == nil || fd.SpriteIndex < 0 || fd.SpriteIndex >= len(anim.Sprites) || anim.Sprites[fd.SpriteIndex] == nil {
@@ -308,12 +427,68 @@ func (ed *CharacterEditor) drawFrameDataWindow() {
 	if imgui.Checkbox("Can OTG", &fd.CanOTG) {
 		ed.markDirty()
 	}
+	juggleCost := int32(fd.JuggleCost)
+	if imgui.InputInt("Juggle Cost", &juggleCost) {
+		if juggleCost < 0 {
+			juggleCost = 0
+		}
+		fd.JuggleCost = int(juggleCost)
+		ed.markDirty()
+	}
 	if imgui.Checkbox("Is Invincible", &fd.IsInvincible) {
 		ed.markDirty()
 	}
+	if imgui.Checkbox("Strike Invincible", &fd.StrikeInvincible) {
+		ed.markDirty()
+	}
+	if imgui.Checkbox("Throw Invincible", &fd.ThrowInvincible) {
+		ed.markDirty()
+	}
+	if imgui.Checkbox("Projectile Invincible", &fd.ProjectileInvincible) {
+		ed.markDirty()
+	}
 	if imgui.Checkbox("Has Armor", &fd.HasArmor) {
 		ed.markDirty()
 	}
+	armorHits := int32(fd.ArmorHits)
+	if imgui.InputInt("Armor Hits", &armorHits) {
+		if armorHits < 0 {
+			armorHits = 0
+		}
+		fd.ArmorHits = int(armorHits)
+		ed.markDirty()
+	}
+}
+
+func (ed *CharacterEditor) drawSpawnDataEditor(spawn *animation.SpawnData) {
+	if imgui.InputTextWithHint("Spawn Animation", "fireball", &spawn.Animation, 0, nil) {
+		ed.markDirty()
+	}
+	offset := [2]float32{float32(spawn.Offset.X), float32(spawn.Offset.Y)}
+	if imgui.InputFloat2("Spawn Offset", &offset) {
+		spawn.Offset = types.Vector2{X: float64(offset[0]), Y: float64(offset[1])}
+		ed.markDirty()
+	}
+	velocity := [2]float32{float32(spawn.Velocity.X), float32(spawn.Velocity.Y)}
+	if imgui.InputFloat2("Spawn Velocity", &velocity) {
+		spawn.Velocity = types.Vector2{X: float64(velocity[0]), Y: float64(velocity[1])}
+		ed.markDirty()
+	}
+	lifetime := int32(spawn.Lifetime)
+	if imgui.InputInt("Lifetime", &lifetime) {
+		spawn.Lifetime = max(int(lifetime), 0)
+		ed.markDirty()
+	}
+	hits := int32(spawn.Hits)
+	if imgui.InputInt("Spawn Hits", &hits) {
+		spawn.Hits = max(int(hits), 0)
+		ed.markDirty()
+	}
+	hitInterval := int32(spawn.HitInterval)
+	if imgui.InputInt("Hit Interval", &hitInterval) {
+		spawn.HitInterval = max(int(hitInterval), 0)
+		ed.markDirty()
+	}
 }
 
 func (ed *CharacterEditor) drawBoxEditorWindow() {
@@ -334,7 +509,7 @@ func (ed *CharacterEditor) drawBoxEditorWindow() {
 		fd.Boxes = make(map[types.BoxType][]types.Rect)
 	}
 
-	boxTypeNames := []string{"Collision", "Hit", "Hurt"}
+	boxTypeNames := []string{"Collision", "Hit", "Hurt", "Throw", "Throwable"}
 	typeIndex := int32(ed.selectedBoxType)
 	if imgui.ComboStrarrV("Box Type", &typeIndex, boxTypeNames, int32(len(boxTypeNames)), -1) {
 		ed.selectedBoxType = types.BoxType(typeIndex)
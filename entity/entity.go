@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"fgengine/animation"
+	"fgengine/constants"
+	"fgengine/types"
+)
+
+// Entity is anything spawned by a move that lives apart from its character, like projectiles and traps
+type Entity struct {
+	Owner        int // index of the player that spawned it
+	AnimPlayer   *animation.AnimationPlayer
+	Position     types.FixedVector2
+	Velocity     types.FixedVector2
+	IsFacingLeft animation.Orientation
+
+	Lifetime    int // frames left, 0 means no limit
+	HitsLeft    int
+	HitInterval int
+	HitCooldown int // frames left before it can hit again
+	Hitstop     int // frames left frozen
+	Despawned   bool
+}
+
+// Spawn creates the entity described by a frame of the owner, the owner's animations are shared with it.
+func Spawn(owner int, sm *animation.StateMachine, spawn *animation.SpawnData) *Entity {
+	direction := types.FixedOne
+	if sm.IsFacingLeft == animation.Left {
+		direction = -direction
+	}
+	offset := spawn.Offset.Fixed()
+	velocity := spawn.Velocity.Fixed()
+
+	hits := spawn.Hits
+	if hits <= 0 {
+		hits = 1
+	}
+
+	e := &Entity{
+		Owner: owner,
+		AnimPlayer: new(animation.AnimationPlayer{
+			Animations: sm.AnimPlayer.Animations,
+		}),
+		Position: types.FixedVector2{
+			X: sm.Position.X + offset.X.Mul(direction),
+			Y: sm.Position.Y + offset.Y,
+		},
+		Velocity:     types.FixedVector2{X: velocity.X.Mul(direction), Y: velocity.Y},
+		IsFacingLeft: sm.IsFacingLeft,
+		Lifetime:     spawn.Lifetime,
+		HitsLeft:     hits,
+		HitInterval:  spawn.HitInterval,
+	}
+	e.AnimPlayer.SetAnimation(spawn.Animation)
+	// spawns of the entity's own frames aren't supported
+	e.AnimPlayer.FrameStarted = false
+	e.AnimPlayer.AnimationStarted = false
+	if e.AnimPlayer.ActiveAnimation == nil {
+		e.Despawned = true
+	}
+	return e
+}
+
+// Update moves the entity and advances its animation, it despawns when its lifetime ends or it leaves the world.
+func (e *Entity) Update() {
+	if e.Despawned {
+		return
+	}
+	if e.Hitstop > 0 {
+		e.Hitstop--
+		return
+	}
+
+	if e.HitCooldown > 0 {
+		e.HitCooldown--
+	}
+
+	e.Position.X += e.Velocity.X
+	e.Position.Y += e.Velocity.Y
+
+	// the animation name is passed as the intent so loop frames keep looping
+	e.AnimPlayer.Update(e.AnimPlayer.ActiveAnimationName())
+	e.AnimPlayer.FrameStarted = false
+
+	if e.Lifetime > 0 {
+		e.Lifetime--
+		if e.Lifetime == 0 {
+			e.Despawned = true
+		}
+	}
+	if !constants.FixedWorld.Contains(e.Position) {
+		e.Despawned = true
+	}
+}
+
+// CanHit returns true if the entity has hits left and isn't waiting between two hits
+func (e *Entity) CanHit() bool {
+	return !e.Despawned && e.HitsLeft > 0 && e.HitCooldown == 0
+}
+
+// RegisterHit spends one hit and freezes the entity, it despawns once it runs out of hits.
+func (e *Entity) RegisterHit(hitstop int) {
+	e.HitsLeft--
+	e.HitCooldown = e.HitInterval
+	e.Hitstop = max(e.Hitstop, hitstop)
+	if e.HitsLeft <= 0 {
+		e.Despawned = true
+	}
+}
+
+// Direction returns the sign of the X axis the entity is facing
+func (e *Entity) Direction() int {
+	if e.IsFacingLeft == animation.Left {
+		return -1
+	}
+	return 1
+}
+
+func (e *Entity) Sprite() *animation.Sprite {
+	return e.AnimPlayer.ActiveSprite()
+}
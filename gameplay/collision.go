@@ -3,9 +3,10 @@ package gameplay
 import (
 	"fgengine/animation"
 	"fgengine/types"
-	"math"
 )
 
+const pushFriction = types.FixedOne * 9 / 10 // speed kept by the pushing player when it transfers its speed
+
 // ResolveBodyCollision checks for collisions on the collision boxes, then moves the players out of each other if they overlap.
 func ResolveBodyCollision(p1, p2 *animation.StateMachine) {
 	p1CollisionBox, ok := firstCollisionBoxInWorld(p1)
@@ -23,72 +24,66 @@ func ResolveBodyCollision(p1, p2 *animation.StateMachine) {
 	}
 
 	// center of the collision box
-	ax, ay := p1CollisionBox.Center()
-	bx, by := p2CollisionBox.Center()
-
-	centerA := types.Vector2{X: ax, Y: ay}
-	centerB := types.Vector2{X: bx, Y: by}
-
-	delta := centerB.Sub(centerA)
+	delta := p2CollisionBox.Center().Sub(p1CollisionBox.Center())
 
-	overlapX := math.Min(p1CollisionBox.Right(), p2CollisionBox.Right()) - math.Max(p1CollisionBox.X, p2CollisionBox.X)
-	overlapY := math.Min(p1CollisionBox.Bottom(), p2CollisionBox.Bottom()) - math.Max(p1CollisionBox.Y, p2CollisionBox.Y)
+	overlapX := min(p1CollisionBox.Right(), p2CollisionBox.Right()) - max(p1CollisionBox.X, p2CollisionBox.X)
+	overlapY := min(p1CollisionBox.Bottom(), p2CollisionBox.Bottom()) - max(p1CollisionBox.Y, p2CollisionBox.Y)
 	if overlapX <= 0 || overlapY <= 0 {
 		return
 	}
 
-	var separationValue types.Vector2
+	var separationValue types.FixedVector2
 
 	// resolve in the direction of least penetration 🥵
 	if overlapX < overlapY {
 		if delta.X > 0 {
-			separationValue = types.Vector2{X: -overlapX, Y: 0}
+			separationValue = types.FixedVector2{X: -overlapX, Y: 0}
 		} else {
-			separationValue = types.Vector2{X: overlapX, Y: 0}
+			separationValue = types.FixedVector2{X: overlapX, Y: 0}
 		}
 
 		resolveWithVelocity(p1, p2, separationValue, true)
 
 	} else {
 		if delta.Y > 0 {
-			separationValue = types.Vector2{X: 0, Y: -overlapY}
+			separationValue = types.FixedVector2{X: 0, Y: -overlapY}
 		} else {
-			separationValue = types.Vector2{X: 0, Y: overlapY}
+			separationValue = types.FixedVector2{X: 0, Y: overlapY}
 		}
 
 		resolveWithVelocity(p1, p2, separationValue, false)
 	}
 }
 
-func firstCollisionBoxInWorld(sm *animation.StateMachine) (types.Rect, bool) {
+func firstCollisionBoxInWorld(sm *animation.StateMachine) (types.FixedRect, bool) {
 	if sm == nil || sm.AnimPlayer == nil {
-		return types.Rect{}, false
+		return types.FixedRect{}, false
 	}
 
 	frameData := sm.AnimPlayer.ActiveFrameData()
 	if frameData == nil {
-		return types.Rect{}, false
+		return types.FixedRect{}, false
 	}
 
 	boxes := frameData.Boxes[types.Collision]
 	if len(boxes) == 0 {
-		return types.Rect{}, false
+		return types.FixedRect{}, false
 	}
 
 	return boxInWorldCoordinates(boxes[0], sm)
 }
 
-func resolveWithVelocity(a, b *animation.StateMachine, separationValue types.Vector2, isX bool) {
+func resolveWithVelocity(a, b *animation.StateMachine, separationValue types.FixedVector2, isX bool) {
 	// magnitude da velocidade
-	la := math.Hypot(a.Velocity.X, a.Velocity.Y)
-	lb := math.Hypot(b.Velocity.X, b.Velocity.Y)
+	la := a.Velocity.Length()
+	lb := b.Velocity.Length()
 
 	total := la + lb
-	fa := 0.5
-	fb := 0.5
+	fa := types.FixedOne / 2
+	fb := types.FixedOne / 2
 	if total > 0 {
-		fa = la / total
-		fb = lb / total
+		fa = la.Div(total)
+		fb = lb.Div(total)
 	}
 
 	// aplica separação
@@ -106,27 +101,27 @@ func resolveWithVelocity(a, b *animation.StateMachine, separationValue types.Vec
 
 func applyPushVelocity(a, b *animation.StateMachine, isX bool) {
 	if isX {
-		ax := math.Abs(a.Velocity.X)
-		bx := math.Abs(b.Velocity.X)
+		ax := a.Velocity.X.Abs()
+		bx := b.Velocity.X.Abs()
 
 		if ax > bx {
 			b.Velocity.X = a.Velocity.X
-			a.Velocity.X *= 0.9
+			a.Velocity.X = a.Velocity.X.Mul(pushFriction)
 		} else if bx > ax {
 			a.Velocity.X = b.Velocity.X
-			b.Velocity.X *= 0.9
+			b.Velocity.X = b.Velocity.X.Mul(pushFriction)
 		}
 		return
 	}
 
-	ay := math.Abs(a.Velocity.Y)
-	by := math.Abs(b.Velocity.Y)
+	ay := a.Velocity.Y.Abs()
+	by := b.Velocity.Y.Abs()
 
 	if ay > by {
 		b.Velocity.Y = a.Velocity.Y
-		a.Velocity.Y *= 0.9
+		a.Velocity.Y = a.Velocity.Y.Mul(pushFriction)
 	} else if by > ay {
 		a.Velocity.Y = b.Velocity.Y
-		b.Velocity.Y *= 0.9
+		b.Velocity.Y = b.Velocity.Y.Mul(pushFriction)
 	}
 }
@@ -0,0 +1,80 @@
+package gameplay
+
+// Combo tracks the hits a player lands while the opponent stays in stun, GameState.Combos is indexed by the attacker.
+// A combo is always true, a hit after the defender could act starts a new one and the previous one is kept in
+// GameState.DroppedCombos.
+type Combo struct {
+	Hits          int
+	Damage        int // total damage after scaling
+	StarterScale  int // percent applied to every hit, set by the move that started the combo
+	FramesOfGap   int // frames the defender has been free to act since the last hit
+	FramesOfCombo int // frames since the combo started
+	JugglePoints  int // juggle budget spent, see Rules.JuggleLimit
+}
+
+// Active returns true while the combo counter is running
+func (c *Combo) Active() bool {
+	return c.Hits > 0
+}
+
+// registerComboHit counts a hit on the attacker's combo and returns the damage after scaling. A hit after the defender
+// was free to act starts a new combo, the previous one is kept as dropped.
+func (g *GameState) registerComboHit(attackerIndex, damage, starterScaling int) int {
+	combo := &g.Combos[attackerIndex]
+	if !combo.Active() || combo.FramesOfGap > 0 {
+		if combo.Active() {
+			g.DroppedCombos[attackerIndex] = *combo
+		}
+		*combo = Combo{StarterScale: 100}
+		if starterScaling > 0 {
+			combo.StarterScale = starterScaling
+		}
+	}
+
+	scaled := damage * g.Rules.comboScaling(combo.Hits) / 100 * combo.StarterScale / 100
+	if minimum := damage * g.Rules.MinimumDamagePercent / 100; scaled < minimum {
+		scaled = minimum
+	}
+
+	combo.Hits++
+	combo.Damage += scaled
+	combo.FramesOfGap = 0
+	return scaled
+}
+
+// breakCombo ends the attacker's combo, used when the defender blocks
+func (g *GameState) breakCombo(attackerIndex int) {
+	g.Combos[attackerIndex] = Combo{}
+}
+
+// updateCombos counts the frames each defender is free to act, combos reset once the gap is longer than the rules allow.
+func (g *GameState) updateCombos() {
+	for attackerIndex := range g.Combos {
+		combo := &g.Combos[attackerIndex]
+		if !combo.Active() {
+			continue
+		}
+
+		combo.FramesOfCombo++
+		defender := g.Characters[1-attackerIndex].StateMachine
+		if !defender.IsActionable() {
+			continue
+		}
+
+		combo.FramesOfGap++
+		if combo.FramesOfGap > g.Rules.ComboGapTolerance {
+			g.breakCombo(attackerIndex)
+		}
+	}
+}
+
+// comboScaling returns the damage percent for the hit at index hitsBefore, the last value of the table repeats
+func (r *Rules) comboScaling(hitsBefore int) int {
+	if len(r.ComboScaling) == 0 {
+		return 100
+	}
+	if hitsBefore >= len(r.ComboScaling) {
+		return r.ComboScaling[len(r.ComboScaling)-1]
+	}
+	return r.ComboScaling[hitsBefore]
+}
@@ -0,0 +1,38 @@
+package gameplay
+
+import "testing"
+
+func TestComboRestartsAfterGap(t *testing.T) {
+	g := &GameState{Rules: DefaultRules()}
+	g.Rules.ComboScaling = []int{100, 80}
+	g.Rules.MinimumDamagePercent = 10
+
+	if damage := g.registerComboHit(0, 1000, 0); damage != 1000 {
+		t.Fatalf("starter dealt %d, want 1000", damage)
+	}
+	if damage := g.registerComboHit(0, 1000, 0); damage != 800 {
+		t.Fatalf("second hit dealt %d, want 800", damage)
+	}
+
+	// the defender could act for a frame, the next hit is a fresh punish
+	g.Combos[0].FramesOfGap = 1
+	if damage := g.registerComboHit(0, 1000, 0); damage != 1000 {
+		t.Fatalf("hit after the gap dealt %d, want the unscaled 1000", damage)
+	}
+	if combo := g.Combos[0]; combo.Hits != 1 || combo.Damage != 1000 {
+		t.Fatalf("got %+v, want a new combo of 1 hit", combo)
+	}
+	if dropped := g.DroppedCombos[0]; dropped.Hits != 2 || dropped.Damage != 1800 {
+		t.Fatalf("dropped %+v, want the 2 hits before the gap", dropped)
+	}
+
+	if g.registerComboHit(0, 1000, 0); g.Combos[0].Hits != 2 || g.DroppedCombos[0].Hits != 2 {
+		t.Fatalf("got %+v and dropped %+v, the new combo keeps counting", g.Combos[0], g.DroppedCombos[0])
+	}
+
+	// a blocked hit ends the combo without a drop
+	g.breakCombo(0)
+	if g.registerComboHit(0, 1000, 0); g.DroppedCombos[0].Hits != 2 {
+		t.Fatalf("dropped %+v, the combo ended by the block isn't a drop", g.DroppedCombos[0])
+	}
+}
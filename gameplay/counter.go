@@ -0,0 +1,74 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+)
+
+// CounterType tells if a hit interrupted an attack of the defender
+type CounterType uint8
+
+const (
+	CounterNone   CounterType = iota
+	CounterHit                // defender was hit during startup or active frames
+	CounterPunish             // defender was hit during recovery
+)
+
+func (c CounterType) String() string {
+	switch c {
+	case CounterNone:
+		return "None"
+	case CounterHit:
+		return "Counter"
+	case CounterPunish:
+		return "Punish"
+	default:
+		return "Unknown"
+	}
+}
+
+// CounterModifiers are percentages applied to a hit that counters, 100 keeps the frame data value
+type CounterModifiers struct {
+	DamagePercent  int `yaml:"damagePercent"`
+	HitstunPercent int `yaml:"hitstunPercent"` // grounded defenders
+	UntechPercent  int `yaml:"untechPercent"`  // airborne defenders, their hitstun is the time before they can tech
+}
+
+// counterType classifies the hit by the move phase the defender was in when it landed
+func counterType(defender *animation.StateMachine) CounterType {
+	switch defender.AnimPlayer.ActivePhase() {
+	case animation.PhaseStartup, animation.PhaseActive:
+		return CounterHit
+	case animation.PhaseRecovery:
+		return CounterPunish
+	default:
+		return CounterNone
+	}
+}
+
+// counterModifiers returns the rule modifiers for the counter type, nil when the hit isn't a counter
+func (r *Rules) counterModifiers(counter CounterType) *CounterModifiers {
+	switch counter {
+	case CounterHit:
+		return &r.CounterHit
+	case CounterPunish:
+		return &r.PunishCounter
+	default:
+		return nil
+	}
+}
+
+// applyCounterModifiers scales damage and hitstun of a counter hit, launched or airborne defenders use the untech modifier for hitstun.
+func (r *Rules) applyCounterModifiers(counter CounterType, airborne bool, damage, hitstun int) (int, int) {
+	modifiers := r.counterModifiers(counter)
+	if modifiers == nil {
+		return damage, hitstun
+	}
+
+	damage = damage * modifiers.DamagePercent / 100
+	if airborne {
+		hitstun = hitstun * modifiers.UntechPercent / 100
+	} else {
+		hitstun = hitstun * modifiers.HitstunPercent / 100
+	}
+	return damage, hitstun
+}
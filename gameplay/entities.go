@@ -0,0 +1,93 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/entity"
+	"fgengine/types"
+	"slices"
+)
+
+// spawnEntity creates the entity of a frame the player just entered
+func (g *GameState) spawnEntity(playerIndex int, frameData *animation.FrameData) {
+	if frameData.Spawn == nil {
+		return
+	}
+	g.Entities = append(g.Entities, entity.Spawn(playerIndex, g.Characters[playerIndex].StateMachine, frameData.Spawn))
+}
+
+func (g *GameState) removeDespawnedEntities() {
+	g.Entities = slices.DeleteFunc(g.Entities, func(e *entity.Entity) bool {
+		return e.Despawned
+	})
+}
+
+// checkEntityHits looks for entity hitboxes overlapping the hurtboxes of the owner's opponent.
+func (g *GameState) checkEntityHits() []HitEvent {
+	var events []HitEvent
+	for i, e := range g.Entities {
+		if !e.CanHit() || e.Hitstop > 0 {
+			continue
+		}
+		defenderIndex := 1 - e.Owner
+		defender := g.Characters[defenderIndex].StateMachine
+
+		frameData := e.AnimPlayer.ActiveFrameData()
+		defenderFrameData := defender.AnimPlayer.ActiveFrameData()
+		if frameData == nil || defenderFrameData == nil {
+			continue
+		}
+		if !canBeHit(defender, defenderFrameData, frameData, animation.AttackProjectile) {
+			continue
+		}
+
+		for _, hitBox := range frameData.Boxes[types.Hit] {
+			hitBoxWorld := boxInWorld(hitBox, e.Position, e.IsFacingLeft, e.AnimPlayer)
+			if contact, ok := hitsHurtbox(hitBoxWorld, defender, defenderFrameData); ok {
+				events = append(events, HitEvent{
+					Attacker:     e.Owner,
+					Defender:     defenderIndex,
+					FrameData:    frameData,
+					ContactPoint: contact,
+					Projectile:   true,
+					Entity:       i,
+				})
+				break
+			}
+		}
+	}
+	return events
+}
+
+// resolveEntityClashes makes projectiles of opposing players cancel each other, each contact spends one hit of both.
+func (g *GameState) resolveEntityClashes() {
+	for i, first := range g.Entities {
+		for _, second := range g.Entities[i+1:] {
+			if first.Owner == second.Owner || !first.CanHit() || !second.CanHit() {
+				continue
+			}
+			if entityHitboxesOverlap(first, second) {
+				first.RegisterHit(0)
+				second.RegisterHit(0)
+			}
+		}
+	}
+}
+
+func entityHitboxesOverlap(first, second *entity.Entity) bool {
+	firstFrameData := first.AnimPlayer.ActiveFrameData()
+	secondFrameData := second.AnimPlayer.ActiveFrameData()
+	if firstFrameData == nil || secondFrameData == nil {
+		return false
+	}
+
+	for _, firstBox := range firstFrameData.Boxes[types.Hit] {
+		firstWorld := boxInWorld(firstBox, first.Position, first.IsFacingLeft, first.AnimPlayer)
+		for _, secondBox := range secondFrameData.Boxes[types.Hit] {
+			secondWorld := boxInWorld(secondBox, second.Position, second.IsFacingLeft, second.AnimPlayer)
+			if firstWorld.IsOverlapping(secondWorld) {
+				return true
+			}
+		}
+	}
+	return false
+}
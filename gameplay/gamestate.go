@@ -3,40 +3,134 @@ package gameplay
 import (
 	"fgengine/animation"
 	"fgengine/character"
-	"fgengine/constants"
+	"fgengine/entity"
 	"fgengine/input"
+	"fgengine/types"
 	"slices"
 )
 
 type GameState struct {
 	Characters [2]*character.Character
+	Entities   []*entity.Entity // projectiles and anything else spawned by moves, in spawn order
 	inputHist  [2][]input.GameInput
+
+	Rules Rules
+
+	Meter [2]int // resource of each player, capped by the character's max meter
+
+	HitEvents     []HitEvent // hits resolved on the last update
+	Clashed       bool       // attacks clashed on the last update
+	Combos        [2]Combo   // indexed by the attacking player
+	DroppedCombos [2]Combo   // last combo of each attacker dropped by a hit after the defender could act
+	throw         throwSequence
+
+	Knockdowns [2]KnockdownInfo // last knockdown of each player
+	Frame      int              // number of updates since the match started
+
+	SuperFlash SuperFlash
+	Match      Match
+	RNG        RNG
+
+	bufferedIntents [2]input.Intent // last button intent input during hitstop
+	reversalFrames  [2]int          // frames left where a starting move is a reversal
+	reversalMove    [2]bool         // the current move started during the reversal window
+	timeCarry       [2]int          // time scale accumulated by each player, see stepsTime
 }
 
 type playerFrameContext struct {
+	playerIndex     int
 	stateMachine    *animation.StateMachine
 	intentAnimation string
 	wasAirborne     bool
+	frozen          bool // in hitstop, nothing advances this frame
 }
 
 func (g *GameState) Update(inputs [2]input.GameInput) {
+	if g.Match.Round == 0 {
+		g.startRound(1)
+	}
+	inputs = g.matchInputs(inputs)
+
 	p1 := g.Characters[0].StateMachine
 	p2 := g.Characters[1].StateMachine
 
 	g.resolveFacing(p1, p2)
 
+	// Players whose time is stopped, like the opponent during a super flash, don't even count down their hitstop.
+	running := [2]bool{g.stepsTime(0), g.stepsTime(1)}
+	flashing := g.SuperFlash.Active()
+	g.updateSuperFlash()
+
 	frame := [2]playerFrameContext{}
 	for i, sm := range []*animation.StateMachine{p1, p2} {
+		// Inputs are always recorded so motions can be buffered during stun.
 		g.pushInputToHistory(i, inputs[i])
 
+		frozen := !running[i] || sm.Hitstop > 0
+		if running[i] && sm.Hitstop > 0 {
+			sm.Hitstop--
+		} else if !frozen {
+			wasActionable := sm.IsActionable()
+			if g.reversalFrames[i] > 0 {
+				g.reversalFrames[i]--
+			}
+			tickReaction(sm)
+			if !wasActionable && sm.IsActionable() {
+				g.reversalFrames[i] = g.Rules.ReversalWindow
+			}
+			if canAirTech(sm) && g.buttonPressed(i, techButtons) {
+				airTech(sm)
+			}
+			if canChooseRise(sm) && g.buttonPressed(i, techButtons) {
+				g.chooseRise(i)
+			}
+		}
+
+		intentAnimation := ""
+		sm.ProximityGuard = false
+		commands := g.Characters[i].Commands
+		if frozen && sm.IsActionable() && !g.throw.active {
+			// Buttons pressed during hitstop come out when it ends, so cancels can be input during the freeze.
+			intent := input.CheckInputIntent(commands, correctInputByFacing(g.inputHist[i], sm.IsFacingLeft))
+			if intent.Button {
+				g.bufferedIntents[i] = intent
+			}
+		}
+		if !frozen && sm.IsActionable() && !g.throw.active {
+			intent := input.CheckInputIntent(commands, correctInputByFacing(g.inputHist[i], sm.IsFacingLeft))
+			if !intent.Button && g.bufferedIntents[i].Animation != "" {
+				intent = g.bufferedIntents[i]
+			}
+			intentAnimation = intent.Animation
+			sm.Crouching = !sm.IsAirborne() && inputs[i].IsPressed(input.Down)
+
+			// Holding back near an active hitbox stops walking and shows the block pose, attacks still come out.
+			// A move in progress is never replaced by the pose.
+			opponent := g.Characters[1-i].StateMachine
+			inNeutral := sm.AnimPlayer.IsFinished() || sm.AnimPlayer.ActivePhase() == animation.PhaseNone
+			if inNeutral && isBackIntent(intentAnimation) && hitboxNear(opponent, sm, types.IntToFixed(proximityGuardDistance)) {
+				sm.ProximityGuard = true
+				intentAnimation = ""
+			}
+		}
+		if !frozen {
+			g.bufferedIntents[i] = input.Intent{}
+		}
+
 		frame[i] = playerFrameContext{
+			playerIndex:     i,
 			stateMachine:    sm,
-			intentAnimation: input.CheckInputIntent(correctInputByFacing(g.inputHist[i], sm.IsFacingLeft)),
+			intentAnimation: intentAnimation,
 			wasAirborne:     sm.IsAirborne(),
+			frozen:          frozen,
 		}
 	}
 
 	for _, ctx := range frame {
+		if ctx.frozen {
+			continue
+		}
+
 		// Apply velocity from the framedata
 		ctx.stateMachine.ApplyVelocity()
 
@@ -47,16 +141,69 @@ func (g *GameState) Update(inputs [2]input.GameInput) {
 		ctx.stateMachine.ApplyPhysics()
 	}
 
+	for _, e := range g.Entities {
+		if running[e.Owner] {
+			e.Update()
+		}
+	}
+
+	// Hits are collected for both players first, then applied, so neither side gets an advantage from the evaluation order.
+	// Throws are resolved before strikes, a grabbed player's attack doesn't come out.
+	g.resolveThrows()
+	g.Clashed = false
+	if g.Rules.Clashes && checkClash(p1, p2) {
+		g.resolveClash(p1, p2)
+	}
+	g.resolveEntityClashes()
+	g.HitEvents = append(resolveTrades(CheckHits(p1, p2), g.Rules.Trade), g.checkEntityHits()...)
+	g.resolveHits(g.HitEvents)
+	g.updateThrow(running)
+
 	// Resolve player pushbox overlap once after both players have integrated physics.
-	CheckHits(p1, p2)
 	ResolveBodyCollision(p1, p2)
 
 	for _, ctx := range frame {
+		if ctx.frozen {
+			continue
+		}
+
 		// Checking for landing/falling/idle animations after physics has been applied, as the animation may depend on whether the character is airborne or not.
 		g.applyAnimationPostPhysics(ctx)
 
 		// some animations may need info on the input to check some logic
 		ctx.stateMachine.AnimPlayer.Update(ctx.intentAnimation)
+
+		g.triggerFrameEvents(ctx.playerIndex)
+	}
+
+	g.removeDespawnedEntities()
+	g.updateCombos()
+	g.updateMatch(flashing)
+	g.Frame++
+}
+
+// triggerFrameEvents runs what happens once when a frame starts, like spawns, meter costs and super flashes.
+func (g *GameState) triggerFrameEvents(playerIndex int) {
+	player := g.Characters[playerIndex].StateMachine.AnimPlayer
+	if !player.FrameStarted {
+		return
+	}
+	player.FrameStarted = false
+	animationStarted := player.AnimationStarted
+	player.AnimationStarted = false
+
+	if player.FrameIndex == 0 {
+		g.reversalMove[playerIndex] = g.reversalFrames[playerIndex] > 0
+	}
+
+	frameData := player.ActiveFrameData()
+	if frameData == nil {
+		return
+	}
+	g.applyFrameMeter(playerIndex, player, frameData, animationStarted)
+	g.spawnEntity(playerIndex, frameData)
+	if frameData.SuperFlash > 0 {
+		g.startSuperFlash(playerIndex, frameData.SuperFlash)
 	}
 }
 
@@ -79,15 +226,24 @@ func (g *GameState) resolveFacing(p1, p2 *animation.StateMachine) {
 	}
 }
 
-// pushInputToHistory adds a new input to the player's input history, and ensures the history doesn't exceed the maximum length defined in constants.
+// pushInputToHistory adds a new input to the player's input history, and ensures the history doesn't exceed what the character's commands need.
 func (g *GameState) pushInputToHistory(playerIndex int, in input.GameInput) {
 	history := append(g.inputHist[playerIndex], in)
-	if len(history) > constants.MaxInputHistory {
-		history = history[1:]
+	if length := g.Characters[playerIndex].InputHistoryLength(); len(history) > length {
+		history = history[len(history)-length:]
 	}
 	g.inputHist[playerIndex] = history
 }
 
+// heldInput returns the latest input of the player with directions relative to its facing, so Left always means back.
+func (g *GameState) heldInput(playerIndex int) input.GameInput {
+	history := g.inputHist[playerIndex]
+	if len(history) == 0 {
+		return input.NoInput
+	}
+	return correctDirectionByFacing(history[len(history)-1], g.Characters[playerIndex].StateMachine.IsFacingLeft)
+}
+
 func correctInputByFacing(history []input.GameInput, facing animation.Orientation) []input.GameInput {
 	if facing != animation.Left {
 		return history
@@ -95,23 +251,51 @@ func correctInputByFacing(history []input.GameInput, facing animation.Orientatio
 
 	corrected := make([]input.GameInput, 0, len(history))
 	for _, gInput := range history {
-		if gInput&input.Left != 0 {
-			gInput = (gInput &^ input.Left) | input.Right
-		} else if gInput&input.Right != 0 {
-			gInput = (gInput &^ input.Right) | input.Left
-		}
-		corrected = append(corrected, gInput)
+		corrected = append(corrected, correctDirectionByFacing(gInput, facing))
 	}
 
 	return corrected
 }
 
+func correctDirectionByFacing(gInput input.GameInput, facing animation.Orientation) input.GameInput {
+	if facing != animation.Left {
+		return gInput
+	}
+	if gInput&input.Left != 0 {
+		gInput = (gInput &^ input.Left) | input.Right
+	} else if gInput&input.Right != 0 {
+		gInput = (gInput &^ input.Right) | input.Left
+	}
+	return gInput
+}
+
+// isBackIntent returns true for the intents produced by holding back, with or without down
+func isBackIntent(intentAnimation string) bool {
+	return intentAnimation == "4" || intentAnimation == "1"
+}
+
 func (g *GameState) applyAnimationPostPhysics(ctx playerFrameContext) {
 	sm := ctx.stateMachine
 	if sm == nil || sm.AnimPlayer == nil {
 		return
 	}
 
+	// reactions own the animation until the player recovers
+	if sm.Reaction != animation.ReactionNone {
+		g.updateReactionPostPhysics(ctx)
+		return
+	}
+
+	if sm.ProximityGuard {
+		setBlockAnimation(sm, sm.Crouching)
+		return
+	}
+
+	// moves without enough meter don't start
+	if !g.canAfford(ctx.playerIndex, ctx.intentAnimation) {
+		ctx.intentAnimation = ""
+	}
+
 	isAirborne := sm.IsAirborne()
 	landedThisFrame := ctx.wasAirborne && !isAirborne
 
@@ -170,23 +354,23 @@ func (g *GameState) checkCancelAnim(ctx playerFrameContext) {
 		return
 	}
 
-	if !canCancelTo(frameData, sm, ctx.intentAnimation) {
+	if !canCancelTo(frameData, sm, ctx.intentAnimation, g.Meter[ctx.playerIndex]) {
 		return
 	}
 
 	sm.AnimPlayer.SetAnimation(ctx.intentAnimation)
 }
 
-func canCancelTo(frameData *animation.FrameData, sm *animation.StateMachine, intentAnimation string) bool {
+func canCancelTo(frameData *animation.FrameData, sm *animation.StateMachine, intentAnimation string, meter int) bool {
 	if intentAnimation == "" {
 		return false
 	}
 
-	if sm.AnimPlayer.ActiveAnimationName() == intentAnimation {
+	if meterCost(sm, intentAnimation) > meter {
 		return false
 	}
 
-	if len(frameData.CancelTypes) == 0 {
+	if sm.AnimPlayer.ActiveAnimationName() == intentAnimation {
 		return false
 	}
 
@@ -195,6 +379,14 @@ func canCancelTo(frameData *animation.FrameData, sm *animation.StateMachine, int
 		return false
 	}
 
+	if sm.ClashCancelFrames > 0 {
+		return true
+	}
+
+	if len(frameData.CancelTypes) == 0 {
+		return false
+	}
+
 	if frameData.CancelTypes[0] == "any" {
 		return true
 	}
@@ -0,0 +1,128 @@
+package gameplay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fgengine/input"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden traces in testdata/golden")
+
+// settle lets both players land from their starting height before the case starts
+const settle = "30 5 5\n"
+
+var goldenCases = []struct {
+	name   string
+	script string       // see input.ReadScript
+	rules  func(*Rules) // changes the default rules, nil keeps them
+}{
+	{"walk_forward", settle + "40 6 5\n10 5 5", nil},
+	{"walk_back", settle + "40 4 5\n10 5 5", nil},
+	{"jump_neutral", settle + "1 8 5\n50 5 5", nil},
+	{"jump_forward", settle + "1 9 5\n50 5 5", nil},
+	{"jump_back", settle + "1 7 5\n50 5 5", nil},
+	{"jump_held", settle + "60 8 5", nil},
+	{"dash", settle + "2 6 5\n2 5 5\n12 6 5\n20 5 5", nil},
+	{"dash_into_a", settle + "1 6 5\n1 5 5\n5 6 5\n1 6A 5\n30 5 5", nil},
+	{"dash_held_into_a", settle + "1 6 5\n1 5 5\n30 6 5\n1 6A 5\n30 5 5", nil},
+	{"special_236A", settle + "1 2 5\n1 3 5\n1 6 5\n1 6A 5\n60 5 5", nil},
+	{"special_236A_lenient", settle + "2 1 5\n2 5 5\n1 9A 5\n60 5 5", nil},
+	{"special_236A_too_slow", settle + "1 2 5\n1 3 5\n20 6 5\n1 6A 5\n40 5 5", nil},
+	{"special_236A_negative_edge", settle + "10 5A 5\n1 2A 5\n1 3A 5\n1 6A 5\n1 6 5\n60 5 5", nil},
+	{"charge_46C", settle + "40 4 5\n1 6C 5\n60 5 5", nil},
+	{"charge_46C_too_short", settle + "39 4 5\n1 6C 5\n60 5 5", nil},
+	{"guard_during_own_attack", settle + "60 6 4\n1 5B 5A\n30 4 5", nil},
+	{"proximity_guard_during_attack", settle + "34 6 4\n1 5B 5C\n30 4 5", nil},
+	{"sweep_hard_knockdown", settle + "42 6 4\n1 2C 5\n120 5 5", nil},
+	{"throw", settle + "50 6 4\n1 5D 5\n60 5 5", nil},
+	{"throw_tech", settle + "50 6 4\n1 5D 5\n3 5 5\n1 5 5AB\n40 5 5", nil},
+	{"throw_vs_throw", settle + "50 6 4\n1 5D 5D\n40 5 5", nil},
+	{"trade_both_hit", settle + "50 6 4\n1 5A 5A\n40 5 5", func(r *Rules) { r.Clashes = false }},
+	{"trade_priority", settle + "50 6 4\n1 5C 5\n4 5 5\n1 5 5A\n40 5 5", func(r *Rules) { r.Clashes = false; r.Trade = TradePriority }},
+	{"clash", settle + "50 6 4\n1 5C 5C\n40 5 5", nil},
+	{"clash_cancel", settle + "50 6 4\n1 5C 5C\n21 5 5\n1 5D 5\n40 5 5", nil},
+	{"clash_cancel_too_late", settle + "50 6 4\n1 5C 5C\n33 5 5\n1 5D 5\n40 5 5", nil},
+	{"fireball_blocked", settle + "30 6 4\n15 5 5\n1 2 3\n1 3 3\n1 6 3\n1 6A 3\n60 5 3", nil},
+	{"fireball_multi_hit_blocked", settle + "30 6 4\n15 5 5\n1 2 3\n1 3 3\n1 6 3\n1 6B 3\n90 5 3", nil},
+	{"fireball_clash", settle + "1 2 2\n1 3 1\n1 6 4\n1 6A 4A\n60 5 5", nil},
+	{"fireball_clash_multi_hit", settle + "1 2 2\n1 3 1\n1 6 4\n1 6B 4A\n150 5 5", nil},
+	{"pushbox_walk_into", settle + "120 6 4\n20 5 5", nil},
+	{"pushbox_jump_over", settle + "90 6 5\n1 9 5\n50 5 5", nil},
+	{"cancel_a_into_b", settle + "1 5A 5\n3 5 5\n1 5B 5\n30 5 5", nil},
+	{"cancel_b_into_a_denied", settle + "1 5B 5\n5 5 5\n1 5A 5\n30 5 5", nil},
+	{"cancel_walk_into_a", settle + "10 6 5\n1 6A 5\n20 5 5", nil},
+}
+
+// TestGoldenTraces runs scripted inputs and compares every frame with the committed trace, run with -update after an
+// intended change of behavior and review the diff of testdata/golden
+func TestGoldenTraces(t *testing.T) {
+	for _, c := range goldenCases {
+		t.Run(c.name, func(t *testing.T) {
+			frames, err := input.ReadScript(strings.NewReader(c.script))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			game := newTestGame(t)
+			game.Rules.IntroFrames = 0
+			if c.rules != nil {
+				c.rules(&game.Rules)
+			}
+			var trace bytes.Buffer
+			for _, inputs := range frames {
+				game.Update(inputs)
+				line, err := json.Marshal(game.Trace())
+				if err != nil {
+					t.Fatal(err)
+				}
+				trace.Write(line)
+				trace.WriteByte('\n')
+			}
+
+			path := filepath.Join("testdata", "golden", c.name+".jsonl")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, trace.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			compareTrace(t, path, trace.Bytes())
+		})
+	}
+}
+
+// compareTrace reports the first frame that differs from the golden file, with both versions of it
+func compareTrace(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%v, run the test with -update to create it", err)
+	}
+	defer file.Close()
+
+	expected := bufio.NewScanner(file)
+	actual := bufio.NewScanner(bytes.NewReader(got))
+	for frame := 1; ; frame++ {
+		hasExpected, hasActual := expected.Scan(), actual.Scan()
+		switch {
+		case !hasExpected && !hasActual:
+			return
+		case !hasExpected:
+			t.Fatalf("trace is longer than %s, frame %d: %s", path, frame, actual.Text())
+		case !hasActual:
+			t.Fatalf("trace is shorter than %s, frame %d: %s", path, frame, expected.Text())
+		case expected.Text() != actual.Text():
+			t.Fatalf("frame %d differs from %s\nwant %s\ngot  %s", frame, path, expected.Text(), actual.Text())
+		}
+	}
+}
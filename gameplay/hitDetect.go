@@ -5,20 +5,56 @@ import (
 	"fgengine/types"
 )
 
-func CheckHits(p1, p2 *animation.StateMachine) {
-	checkhit(p1, p2)
-	checkhit(p2, p1)
+// HitEvent describes a hitbox connecting with a hurtbox, players are referenced by their index in GameState.Characters
+type HitEvent struct {
+	Attacker     int
+	Defender     int
+	FrameData    *animation.FrameData // attacker frame that landed the hit
+	ContactPoint types.FixedVector2   // world position of the center of the hitbox/hurtbox overlap
+	Blocked      bool
+	Throw        bool // a throw that went through its tech window
+	Armored      bool // absorbed by the defender's armor, damage is dealt without any reaction
+	Trade        bool // both players hit each other on the same frame
+	Counter      CounterType
+	Reversal     bool // the attack started right after the attacker left hitstun, blockstun or wakeup
+	Whiffed      bool // the hit was dropped during resolution, the juggle limit was reached for example
+	Projectile   bool // landed by an entity of the attacker
+	Entity       int  // index in GameState.Entities when Projectile is set
 }
 
-func checkhit(thisPlayer, otherPlayer *animation.StateMachine) bool {
+// CheckHits looks for hitboxes overlapping hurtboxes in both directions, both players are checked before anything is applied so simultaneous hits are both reported.
+func CheckHits(p1, p2 *animation.StateMachine) []HitEvent {
+	var events []HitEvent
+	if event, ok := checkhit(p1, p2); ok {
+		event.Attacker, event.Defender = 0, 1
+		events = append(events, event)
+	}
+	if event, ok := checkhit(p2, p1); ok {
+		event.Attacker, event.Defender = 1, 0
+		events = append(events, event)
+	}
+	return events
+}
+
+func checkhit(thisPlayer, otherPlayer *animation.StateMachine) (HitEvent, bool) {
 	if thisPlayer == nil || otherPlayer == nil || thisPlayer.AnimPlayer == nil || otherPlayer.AnimPlayer == nil {
-		return false
+		return HitEvent{}, false
+	}
+
+	// this attack instance already landed
+	if thisPlayer.AnimPlayer.AttackConnected {
+		return HitEvent{}, false
 	}
 
 	thisFrameData := thisPlayer.AnimPlayer.ActiveFrameData()
 	otherFrameData := otherPlayer.AnimPlayer.ActiveFrameData()
 	if thisFrameData == nil || otherFrameData == nil {
-		return false
+		return HitEvent{}, false
+	}
+
+	// whiffs without spending the attack, later active frames can still connect
+	if !canBeHit(otherPlayer, otherFrameData, thisFrameData, animation.AttackStrike) {
+		return HitEvent{}, false
 	}
 
 	for _, hitBox := range thisFrameData.Boxes[types.Hit] {
@@ -26,16 +62,138 @@ func checkhit(thisPlayer, otherPlayer *animation.StateMachine) bool {
 		if !ok {
 			continue
 		}
+		if contact, ok := hitsHurtbox(hitBoxWorld, otherPlayer, otherFrameData); ok {
+			return HitEvent{
+				FrameData:    thisFrameData,
+				ContactPoint: contact,
+			}, true
+		}
+	}
+	return HitEvent{}, false
+}
+
+// canBeHit returns true if the defender's state lets an attack of the given kind connect
+func canBeHit(defender *animation.StateMachine, defenderFrameData, attackFrameData *animation.FrameData, kind animation.AttackKind) bool {
+	if defenderFrameData.IsInvulnerableTo(kind) {
+		return false
+	}
+	if defender.Reaction == animation.ReactionKnockdown && !attackFrameData.CanOTG {
+		return false
+	}
+	// wakeup is fully invulnerable
+	return defender.Reaction != animation.ReactionWakeup
+}
 
-		for _, hurtBox := range otherFrameData.Boxes[types.Hurt] {
-			hurtBoxWorld, ok := boxInWorldCoordinates(hurtBox, otherPlayer)
-			if !ok {
-				continue
+// hitsHurtbox returns the contact point of a hitbox in world coordinates with the first defender hurtbox it overlaps
+func hitsHurtbox(hitBoxWorld types.FixedRect, defender *animation.StateMachine, defenderFrameData *animation.FrameData) (types.FixedVector2, bool) {
+	for _, hurtBox := range defenderFrameData.Boxes[types.Hurt] {
+		hurtBoxWorld, ok := boxInWorldCoordinates(hurtBox, defender)
+		if ok && hitBoxWorld.IsOverlapping(hurtBoxWorld) {
+			return hitBoxWorld.Intersection(hurtBoxWorld).Center(), true
+		}
+	}
+	return types.FixedVector2{}, false
+}
+
+// checkClash returns true if active hitboxes of both players overlap, attacks that already connected don't clash.
+func checkClash(p1, p2 *animation.StateMachine) bool {
+	if p1 == nil || p2 == nil || p1.AnimPlayer == nil || p2.AnimPlayer == nil {
+		return false
+	}
+	if p1.AnimPlayer.AttackConnected || p2.AnimPlayer.AttackConnected {
+		return false
+	}
+
+	p1FrameData := p1.AnimPlayer.ActiveFrameData()
+	p2FrameData := p2.AnimPlayer.ActiveFrameData()
+	if p1FrameData == nil || p2FrameData == nil {
+		return false
+	}
+
+	for _, p1HitBox := range p1FrameData.Boxes[types.Hit] {
+		p1HitBoxWorld, ok := boxInWorldCoordinates(p1HitBox, p1)
+		if !ok {
+			continue
+		}
+
+		for _, p2HitBox := range p2FrameData.Boxes[types.Hit] {
+			p2HitBoxWorld, ok := boxInWorldCoordinates(p2HitBox, p2)
+			if ok && p1HitBoxWorld.IsOverlapping(p2HitBoxWorld) {
+				return true
 			}
+		}
+	}
+	return false
+}
+
+// checkThrow returns true if a throw box of the attacker grabs the defender, throws only connect on players that are free to act.
+func checkThrow(attacker, defender *animation.StateMachine) bool {
+	if attacker == nil || defender == nil || attacker.AnimPlayer == nil || defender.AnimPlayer == nil {
+		return false
+	}
+	if attacker.AnimPlayer.AttackConnected || defender.Reaction != animation.ReactionNone || defender.ThrowInvulnerable > 0 {
+		return false
+	}
+
+	attackerFrameData := attacker.AnimPlayer.ActiveFrameData()
+	defenderFrameData := defender.AnimPlayer.ActiveFrameData()
+	if attackerFrameData == nil || defenderFrameData == nil || len(attackerFrameData.Boxes[types.Throw]) == 0 {
+		return false
+	}
+	if defenderFrameData.IsInvulnerableTo(animation.AttackThrow) {
+		return false
+	}
+
+	throwableBoxes := defenderFrameData.Boxes[types.Throwable]
+	if len(throwableBoxes) == 0 {
+		// without explicit throwable boxes only grounded characters can be grabbed, by their collision box
+		if defender.IsAirborne() {
+			return false
+		}
+		throwableBoxes = defenderFrameData.Boxes[types.Collision]
+	}
+
+	for _, throwBox := range attackerFrameData.Boxes[types.Throw] {
+		throwBoxWorld, ok := boxInWorldCoordinates(throwBox, attacker)
+		if !ok {
+			continue
+		}
+
+		for _, throwableBox := range throwableBoxes {
+			throwableBoxWorld, ok := boxInWorldCoordinates(throwableBox, defender)
+			if ok && throwBoxWorld.IsOverlapping(throwableBoxWorld) {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-			if hitBoxWorld.IsOverlapping(hurtBoxWorld) {
-				// fmt.Println("Hit detected!")
-				// Apply hitstun or other effects here
+// hitboxNear returns true if an active hitbox of the attacker is within distance of any defender hurtbox, used for proximity guard.
+func hitboxNear(attacker, defender *animation.StateMachine, distance types.Fixed) bool {
+	if attacker == nil || defender == nil || attacker.AnimPlayer == nil || defender.AnimPlayer == nil {
+		return false
+	}
+	if attacker.AnimPlayer.AttackConnected {
+		return false
+	}
+
+	attackerFrameData := attacker.AnimPlayer.ActiveFrameData()
+	defenderFrameData := defender.AnimPlayer.ActiveFrameData()
+	if attackerFrameData == nil || defenderFrameData == nil {
+		return false
+	}
+
+	for _, hitBox := range attackerFrameData.Boxes[types.Hit] {
+		hitBoxWorld, ok := boxInWorldCoordinates(hitBox, attacker)
+		if !ok {
+			continue
+		}
+		hitBoxWorld = hitBoxWorld.Expand(distance)
+
+		for _, hurtBox := range defenderFrameData.Boxes[types.Hurt] {
+			hurtBoxWorld, ok := boxInWorldCoordinates(hurtBox, defender)
+			if ok && hitBoxWorld.IsOverlapping(hurtBoxWorld) {
 				return true
 			}
 		}
@@ -43,23 +201,28 @@ func checkhit(thisPlayer, otherPlayer *animation.StateMachine) bool {
 	return false
 }
 
-func boxInWorldCoordinates(box types.Rect, sm *animation.StateMachine) (types.Rect, bool) {
+func boxInWorldCoordinates(box types.Rect, sm *animation.StateMachine) (types.FixedRect, bool) {
 	if sm == nil || sm.AnimPlayer == nil {
-		return types.Rect{}, false
+		return types.FixedRect{}, false
 	}
+	return boxInWorld(box, sm.Position, sm.IsFacingLeft, sm.AnimPlayer), true
+}
 
-	sprite := sm.AnimPlayer.ActiveSprite()
-	anchor := types.Vector2{}
-	if sprite != nil {
-		anchor = sprite.Anchor
+// boxInWorld places a frame box relative to the anchor of the current sprite, shared by characters and entities.
+// Boxes and anchors are authored as floats and converted here, the result is in simulation coordinates.
+func boxInWorld(authoredBox types.Rect, position types.FixedVector2, facing animation.Orientation, player *animation.AnimationPlayer) types.FixedRect {
+	box := authoredBox.Fixed()
+	anchor := types.FixedVector2{}
+	if sprite := player.ActiveSprite(); sprite != nil {
+		anchor = sprite.Anchor.Fixed()
 	}
 
-	worldX := sm.Position.X + box.X - anchor.X
-	if sm.IsFacingLeft == animation.Left {
-		worldX = sm.Position.X - box.X - box.W + anchor.X
+	worldX := position.X + box.X - anchor.X
+	if facing == animation.Left {
+		worldX = position.X - box.X - box.W + anchor.X
 	}
 
-	worldY := sm.Position.Y + box.Y - anchor.Y
+	worldY := position.Y + box.Y - anchor.Y
 
-	return types.Rect{X: worldX, Y: worldY, W: box.W, H: box.H}, true
+	return types.FixedRect{X: worldX, Y: worldY, W: box.W, H: box.H}
 }
@@ -0,0 +1,160 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/input"
+	"fgengine/types"
+)
+
+// resolveHits applies every hit event of the frame, events were all collected before this so the order doesn't change the outcome.
+// Events that end up blocked are flagged in g.HitEvents.
+func (g *GameState) resolveHits(events []HitEvent) {
+	for i, event := range events {
+		attacker := g.Characters[event.Attacker].StateMachine
+		defender := g.Characters[event.Defender].StateMachine
+
+		// players locked in a throw can't strike or be struck, their projectiles still hit
+		if defender.Reaction == animation.ReactionThrown || (!event.Projectile && attacker.Reaction == animation.ReactionThrown) {
+			continue
+		}
+
+		// over the juggle limit the hit whiffs, the attack can still connect on a later frame
+		if !g.spendJugglePoints(event.Attacker, defender, event.FrameData) {
+			g.HitEvents[i].Whiffed = true
+			continue
+		}
+
+		// both sides freeze on contact, trades keep the longest freeze
+		hitstop := g.Rules.hitstop(event.FrameData)
+		defender.Hitstop = max(defender.Hitstop, hitstop)
+		direction := facingDirection(attacker)
+		if event.Projectile {
+			projectile := g.Entities[event.Entity]
+			projectile.RegisterHit(hitstop)
+			direction = projectile.Direction()
+		} else {
+			attacker.AnimPlayer.AttackConnected = true
+			attacker.Hitstop = max(attacker.Hitstop, hitstop)
+			g.HitEvents[i].Reversal = g.reversalMove[event.Attacker]
+		}
+
+		held := g.heldInput(event.Defender)
+		crouching := held.IsPressed(input.Down)
+		if canGuard(defender, held) && event.FrameData.CanBlock(crouching, defender.IsAirborne()) {
+			g.HitEvents[i].Blocked = true
+			g.breakCombo(event.Attacker)
+			applyBlock(defender, event.FrameData, direction, crouching)
+			g.gainMeter(event.Attacker, event.FrameData.MeterGainOnBlock)
+			g.gainMeterFromDamage(event.Defender, event.FrameData.ChipDamage)
+			continue
+		}
+		if absorbWithArmor(defender, event.FrameData) {
+			g.HitEvents[i].Armored = true
+			g.gainMeter(event.Attacker, event.FrameData.MeterGainOnHit)
+			g.gainMeterFromDamage(event.Defender, event.FrameData.Damage)
+			continue
+		}
+
+		counter := counterType(defender)
+		g.HitEvents[i].Counter = counter
+		launched := event.FrameData.Knockup > 0 || defender.IsAirborne()
+		// sweeps knock a standing defender down on the spot, OTG hits pop knocked down ones up instead
+		sweep := !launched && defender.Reaction != animation.ReactionKnockdown &&
+			(event.FrameData.Knockdown || event.FrameData.CanHardKnockdown)
+		damage, hitstun := g.Rules.applyCounterModifiers(counter, launched, event.FrameData.Damage, event.FrameData.Hitstun)
+		damage = g.registerComboHit(event.Attacker, damage, event.FrameData.StarterScaling)
+		applyHit(defender, event.FrameData, direction, damage, hitstun)
+		if sweep {
+			g.startKnockdown(event.Defender)
+		}
+		g.gainMeter(event.Attacker, event.FrameData.MeterGainOnHit)
+		g.gainMeterFromDamage(event.Defender, damage)
+	}
+}
+
+// absorbWithArmor takes the damage without hitstun or pushback if the defender's current frame still has armor left.
+func absorbWithArmor(defender *animation.StateMachine, frameData *animation.FrameData) bool {
+	defenderFrameData := defender.AnimPlayer.ActiveFrameData()
+	if defenderFrameData == nil || frameData.Guard == animation.GuardUnblockable {
+		return false
+	}
+	if defender.AnimPlayer.ArmorHitsAbsorbed >= defenderFrameData.ArmorCapacity() {
+		return false
+	}
+
+	defender.AnimPlayer.ArmorHitsAbsorbed++
+	defender.HP -= frameData.Damage
+	if defender.HP < 0 {
+		defender.HP = 0
+	}
+	defender.FlashFrames = armorFlashFrames
+	return true
+}
+
+// canGuard returns true if the defender is holding back and free to block, players already blocking can keep blocking.
+// Players in their own move can't block until it is over, so they are hit and counter hit.
+func canGuard(defender *animation.StateMachine, held input.GameInput) bool {
+	if !held.IsPressed(input.Left) {
+		return false
+	}
+	if defender.Reaction == animation.ReactionBlock {
+		return true
+	}
+	return defender.IsActionable() && defender.AnimPlayer.ActivePhase() == animation.PhaseNone
+}
+
+// applyBlock deals chip damage and puts the defender in blockstun, the pushback is the same as on hit.
+// direction is the sign of the X axis the attack pushes towards.
+func applyBlock(defender *animation.StateMachine, frameData *animation.FrameData, direction int, crouching bool) {
+	defender.HP -= frameData.ChipDamage
+	if defender.HP < 0 {
+		defender.HP = 0
+	}
+
+	defender.Blockstun = frameData.Blockstun
+	startBlockReaction(defender, crouching)
+
+	defender.Velocity.X = types.IntToFixed(direction * frameData.Pushback)
+}
+
+// applyHit deals the damage, starts hitstun with its hurt animation and pushes the defender away from the attacker.
+// Damage and hitstun are passed apart from the frame data as they can be modified, by counter hits for example.
+func applyHit(defender *animation.StateMachine, frameData *animation.FrameData, direction, damage, hitstun int) {
+	defender.HP -= damage
+	if defender.HP < 0 {
+		defender.HP = 0
+	}
+
+	// OTG hits pop the defender up so it lands in a new knockdown
+	otg := defender.Reaction == animation.ReactionKnockdown
+	launched := frameData.Knockup > 0 || otg
+
+	defender.Hitstun = hitstun
+	defender.KnockdownTimer = 0
+	defender.GroundBounce = frameData.CanGroundBounce
+	defender.WallBounce = frameData.CanWallBounce
+	defender.HardKnockdown = frameData.CanHardKnockdown
+	startHurtReaction(defender, launched)
+
+	// grounded hits use pushback, launchers and airborne hits use knockback
+	if launched || defender.IsAirborne() {
+		defender.Velocity.X = types.IntToFixed(direction * frameData.Knockback)
+	} else {
+		defender.Velocity.X = types.IntToFixed(direction * frameData.Pushback)
+	}
+
+	switch {
+	case frameData.Knockup > 0:
+		defender.Velocity.Y = types.IntToFixed(-frameData.Knockup)
+	case otg:
+		defender.Velocity.Y = types.IntToFixed(-otgPopVelocity)
+	}
+}
+
+// facingDirection returns the sign of the X axis the character is facing
+func facingDirection(sm *animation.StateMachine) int {
+	if sm.IsFacingLeft == animation.Left {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,101 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/constants"
+	"fgengine/input"
+	"fgengine/types"
+)
+
+const (
+	groundBounceVelocity = 10 // upward speed after a ground bounce
+	wallBounceVelocity   = 8  // horizontal speed away from the wall after a wall bounce
+	wallBouncePop        = 6  // upward speed added by a wall bounce
+	otgPopVelocity       = 5  // upward speed of an OTG hit without knockup, so the defender falls into a new knockdown
+	airTechVelocity      = 4  // horizontal speed away from the opponent after an air tech
+
+	techButtons = input.A | input.B | input.C | input.D // any of them air techs once untech time is over, or picks a rise on a soft knockdown
+)
+
+// isJuggled returns true if the defender is being hit while airborne or on the ground after a knockdown
+func isJuggled(defender *animation.StateMachine) bool {
+	return (defender.Reaction == animation.ReactionHurt && defender.IsAirborne()) || defender.Reaction == animation.ReactionKnockdown
+}
+
+// juggleCost returns the juggle points spent by a frame, 0 in the frame data means 1
+func juggleCost(frameData *animation.FrameData) int {
+	if frameData.JuggleCost <= 0 {
+		return 1
+	}
+	return frameData.JuggleCost
+}
+
+// spendJugglePoints returns false when the attacker's combo has no juggle budget left for this hit, the hit whiffs in that case.
+func (g *GameState) spendJugglePoints(attackerIndex int, defender *animation.StateMachine, frameData *animation.FrameData) bool {
+	if !isJuggled(defender) {
+		return true
+	}
+
+	combo := &g.Combos[attackerIndex]
+	cost := juggleCost(frameData)
+	if combo.JugglePoints+cost > g.Rules.JuggleLimit {
+		return false
+	}
+	combo.JugglePoints += cost
+	return true
+}
+
+// canAirTech returns true once an airborne defender's untech time ran out, pending bounces must happen first
+func canAirTech(sm *animation.StateMachine) bool {
+	return sm.Reaction == animation.ReactionHurt && sm.IsAirborne() && sm.Hitstun == 0 && !sm.GroundBounce && !sm.WallBounce
+}
+
+// buttonPressed returns true on the frame any of the buttons is pressed
+func (g *GameState) buttonPressed(playerIndex int, buttons input.GameInput) bool {
+	history := g.inputHist[playerIndex]
+	if len(history) == 0 {
+		return false
+	}
+	current := history[len(history)-1]
+	previous := input.NoInput
+	if len(history) > 1 {
+		previous = history[len(history)-2]
+	}
+	return current&buttons&^previous != 0
+}
+
+// airTech recovers in the air, drifting back from the opponent
+func airTech(sm *animation.StateMachine) {
+	recoverFromReaction(sm)
+	sm.Velocity.X = types.IntToFixed(-facingDirection(sm) * airTechVelocity)
+	sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimAirTech, "fall", "idle")
+}
+
+// bounceOffGround uses the pending ground bounce when an airborne defender lands, returns false if there was none.
+func bounceOffGround(sm *animation.StateMachine) bool {
+	if !sm.GroundBounce {
+		return false
+	}
+	sm.GroundBounce = false
+	sm.Velocity.Y = types.IntToFixed(-groundBounceVelocity)
+	sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimHurtAir, animation.AnimHurtHigh)
+	return true
+}
+
+// bounceOffWall uses the pending wall bounce when an airborne defender touches a world edge.
+func bounceOffWall(sm *animation.StateMachine) {
+	if !sm.WallBounce || !sm.IsAirborne() {
+		return
+	}
+
+	switch {
+	case sm.Position.X <= constants.FixedWorld.X:
+		sm.Velocity.X = types.IntToFixed(wallBounceVelocity)
+	case sm.Position.X >= constants.FixedWorld.Right():
+		sm.Velocity.X = types.IntToFixed(-wallBounceVelocity)
+	default:
+		return
+	}
+	sm.WallBounce = false
+	sm.Velocity.Y = types.IntToFixed(-wallBouncePop)
+}
@@ -0,0 +1,84 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/input"
+)
+
+const backRiseVelocity = 5 // horizontal speed away from the opponent when waking up with a back rise
+
+// KnockdownInfo describes the last knockdown of a player, training mode uses it to show frame advantage on wakeup
+type KnockdownInfo struct {
+	Hard            bool
+	StartFrame      int // GameState.Frame the character hit the ground
+	Duration        int // frames on the ground plus the wakeup animation
+	ActionableFrame int // GameState.Frame the character can act again
+}
+
+// updateDuration recomputes when the character can act again from the time left, called on landing and when a rise is chosen
+func (k *KnockdownInfo) updateDuration(sm *animation.StateMachine, frame int) {
+	k.ActionableFrame = frame + sm.KnockdownTimer + wakeupDuration(sm)
+	k.Duration = k.ActionableFrame - k.StartFrame
+}
+
+// wakeupAnimations returns the animations tried in order for a rise option
+func wakeupAnimations(option animation.WakeupOption) []string {
+	switch option {
+	case animation.WakeupQuickRise:
+		return []string{animation.AnimQuickRise, animation.AnimWakeup}
+	case animation.WakeupBackRise:
+		return []string{animation.AnimBackRise, animation.AnimWakeup}
+	default:
+		return []string{animation.AnimWakeup}
+	}
+}
+
+// wakeupDuration returns the length of the wakeup animation the character will play
+func wakeupDuration(sm *animation.StateMachine) int {
+	for _, name := range wakeupAnimations(sm.WakeupOption) {
+		if anim, exists := sm.AnimPlayer.Animations[name]; exists && anim != nil {
+			return anim.Duration()
+		}
+	}
+	return 0
+}
+
+// canChooseRise returns true while a soft knockdown still has no rise option picked
+func canChooseRise(sm *animation.StateMachine) bool {
+	return sm.Reaction == animation.ReactionKnockdown && !sm.HardKnockdown && sm.WakeupOption == animation.WakeupNormal
+}
+
+// chooseRise picks quick rise, or back rise when holding back, and shortens the time left on the ground.
+func (g *GameState) chooseRise(playerIndex int) {
+	sm := g.Characters[playerIndex].StateMachine
+	sm.WakeupOption = animation.WakeupQuickRise
+	if g.heldInput(playerIndex).IsPressed(input.Left) {
+		sm.WakeupOption = animation.WakeupBackRise
+	}
+	sm.KnockdownTimer = min(sm.KnockdownTimer, g.Rules.QuickRiseFrames)
+
+	g.Knockdowns[playerIndex].updateDuration(sm, g.Frame)
+}
+
+// FramesUntilActionable returns how many frames the player needs before it can act, 0 if it can act now and -1 when it
+// depends on something that can't be predicted, like landing from a juggle.
+func (g *GameState) FramesUntilActionable(playerIndex int) int {
+	sm := g.Characters[playerIndex].StateMachine
+	switch sm.Reaction {
+	case animation.ReactionNone:
+		return max(sm.Hitstun, sm.Blockstun)
+	case animation.ReactionHurt:
+		if sm.IsAirborne() {
+			return -1
+		}
+		return sm.Hitstun
+	case animation.ReactionBlock:
+		return sm.Blockstun
+	case animation.ReactionKnockdown:
+		return g.Knockdowns[playerIndex].ActionableFrame - g.Frame
+	case animation.ReactionWakeup:
+		return max(g.Knockdowns[playerIndex].ActionableFrame-g.Frame, 0)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,240 @@
+package gameplay
+
+import (
+	"fgengine/input"
+)
+
+// MatchPhase is the part of the round the match is in
+type MatchPhase uint8
+
+const (
+	MatchIntro    MatchPhase = iota // round call and "ready", players can't act
+	MatchFight                      // the round is being played
+	MatchKO                         // the round ended by KO, time slows down while the loser falls
+	MatchRoundEnd                   // the result is shown before the next round
+	MatchOver                       // the match is decided
+)
+
+func (p MatchPhase) String() string {
+	switch p {
+	case MatchIntro:
+		return "Intro"
+	case MatchFight:
+		return "Fight"
+	case MatchKO:
+		return "KO"
+	case MatchRoundEnd:
+		return "RoundEnd"
+	case MatchOver:
+		return "Over"
+	default:
+		return "Unknown"
+	}
+}
+
+// RoundEnd is how a round was decided
+type RoundEnd uint8
+
+const (
+	RoundNotOver RoundEnd = iota
+	RoundKO
+	RoundDoubleKO
+	RoundTimeOver
+)
+
+func (r RoundEnd) String() string {
+	switch r {
+	case RoundNotOver:
+		return "NotOver"
+	case RoundKO:
+		return "KO"
+	case RoundDoubleKO:
+		return "DoubleKO"
+	case RoundTimeOver:
+		return "TimeOver"
+	default:
+		return "Unknown"
+	}
+}
+
+// Match tracks the rounds, the timer and the score, it is advanced by GameState.Update
+type Match struct {
+	Phase       MatchPhase
+	PhaseFrames int // frames spent in the current phase
+	Round       int // starts at 1, 0 until the first update
+	Timer       int // frames left in the round, 0 when the rules have no timer
+	Wins        [2]int
+	RoundEnd    RoundEnd // how the last round was decided
+	RoundWinner int      // winner of the last round, -1 on a draw
+	Winner      int      // winner of the match once it is over, -1 on a draw
+}
+
+// Over returns true once the match is decided
+func (m *Match) Over() bool {
+	return m.Phase == MatchOver
+}
+
+func (m *Match) setPhase(phase MatchPhase) {
+	m.Phase = phase
+	m.PhaseFrames = 0
+}
+
+// matchInputs drops the inputs outside of the fight, so players can't act during the intro or after a KO
+func (g *GameState) matchInputs(inputs [2]input.GameInput) [2]input.GameInput {
+	if g.Match.Phase != MatchFight {
+		return [2]input.GameInput{}
+	}
+	return inputs
+}
+
+// updateMatch runs at the end of every frame, it counts down the timer and moves the match through its phases.
+// The timer stops on the frames of a super flash.
+func (g *GameState) updateMatch(flashing bool) {
+	match := &g.Match
+	match.PhaseFrames++
+
+	switch match.Phase {
+	case MatchIntro:
+		if match.PhaseFrames >= g.Rules.IntroFrames {
+			match.setPhase(MatchFight)
+		}
+	case MatchFight:
+		timeOver := false
+		if match.Timer > 0 && !flashing {
+			match.Timer--
+			timeOver = match.Timer == 0
+		}
+		if end := g.checkRoundEnd(timeOver); end != RoundNotOver {
+			g.endRound(end)
+		}
+	case MatchKO:
+		if match.PhaseFrames >= g.Rules.KOFrames {
+			match.setPhase(MatchRoundEnd)
+		}
+	case MatchRoundEnd:
+		if match.PhaseFrames < g.Rules.RoundEndFrames {
+			return
+		}
+		if winner, over := g.matchWinner(); over {
+			match.Winner = winner
+			match.setPhase(MatchOver)
+			return
+		}
+		g.startRound(match.Round + 1)
+	}
+}
+
+func (g *GameState) checkRoundEnd(timeOver bool) RoundEnd {
+	p1KO := g.Characters[0].StateMachine.HP <= 0
+	p2KO := g.Characters[1].StateMachine.HP <= 0
+
+	switch {
+	case p1KO && p2KO:
+		return RoundDoubleKO
+	case p1KO || p2KO:
+		return RoundKO
+	case timeOver:
+		return RoundTimeOver
+	default:
+		return RoundNotOver
+	}
+}
+
+// endRound scores the round, draws score by the draw rule.
+func (g *GameState) endRound(end RoundEnd) {
+	match := &g.Match
+	match.RoundEnd = end
+	match.RoundWinner = g.roundWinner(end)
+
+	switch {
+	case match.RoundWinner >= 0:
+		match.Wins[match.RoundWinner]++
+	case g.Rules.Draw == DrawBothWin:
+		match.Wins[0]++
+		match.Wins[1]++
+	}
+
+	if end == RoundTimeOver {
+		match.setPhase(MatchRoundEnd)
+		return
+	}
+	match.setPhase(MatchKO)
+}
+
+// roundWinner returns the player that won the round, -1 on a draw. On time over the highest health percent wins.
+func (g *GameState) roundWinner(end RoundEnd) int {
+	p1 := g.Characters[0]
+	p2 := g.Characters[1]
+
+	switch end {
+	case RoundKO:
+		if p1.StateMachine.HP <= 0 {
+			return 1
+		}
+		return 0
+	case RoundTimeOver:
+		// compared as cross products to stay in integers
+		p1Health := p1.StateMachine.HP * p2.MaxHealth()
+		p2Health := p2.StateMachine.HP * p1.MaxHealth()
+		if p1Health > p2Health {
+			return 0
+		}
+		if p2Health > p1Health {
+			return 1
+		}
+	}
+	return -1
+}
+
+// matchWinner returns the winner once a player has enough rounds or the last round was played, -1 on a draw
+func (g *GameState) matchWinner() (int, bool) {
+	wins := g.Match.Wins
+	p1Won := wins[0] >= g.Rules.RoundsToWin
+	p2Won := wins[1] >= g.Rules.RoundsToWin
+	lastRound := g.Rules.MaxRounds > 0 && g.Match.Round >= g.Rules.MaxRounds
+
+	switch {
+	case p1Won && p2Won:
+		return -1, true
+	case p1Won:
+		return 0, true
+	case p2Won:
+		return 1, true
+	case !lastRound:
+		return -1, false
+	case wins[0] > wins[1]:
+		return 0, true
+	case wins[1] > wins[0]:
+		return 1, true
+	default:
+		return -1, true
+	}
+}
+
+// startRound puts both characters back at their starting positions with full health, meter carries over between rounds.
+func (g *GameState) startRound(round int) {
+	for i, char := range g.Characters {
+		// loading the character already failed if its initial animation is missing
+		_ = char.Reset(i + 1)
+	}
+
+	g.Entities = nil
+	g.HitEvents = nil
+	g.Clashed = false
+	g.Combos = [2]Combo{}
+	g.DroppedCombos = [2]Combo{}
+	g.throw = throwSequence{}
+	g.Knockdowns = [2]KnockdownInfo{}
+	g.SuperFlash = SuperFlash{}
+	g.bufferedIntents = [2]input.Intent{}
+	g.reversalFrames = [2]int{}
+	g.reversalMove = [2]bool{}
+	g.timeCarry = [2]int{}
+
+	g.Match.Round = round
+	g.Match.Timer = g.Rules.RoundFrames
+	g.Match.RoundEnd = RoundNotOver
+	g.Match.RoundWinner = -1
+	g.Match.Winner = -1
+	g.Match.setPhase(MatchIntro)
+}
@@ -0,0 +1,164 @@
+package gameplay
+
+import (
+	"fgengine/input"
+	"strings"
+	"testing"
+)
+
+func TestMatchOutcomes(t *testing.T) {
+	// both players walk in and press A on the same frame, close enough for the attacks to trade
+	const trade = "50 6 4\n1 5A 5A"
+
+	cases := []struct {
+		name   string
+		rules  func(r *Rules)
+		health [2]int // set when the fight of every round starts
+		script string // inputs of every round from the start of the fight
+		end    RoundEnd
+		rounds int
+		wins   [2]int
+		winner int
+	}{
+		{
+			name:   "time over",
+			rules:  func(r *Rules) { r.RoundFrames = 60 },
+			health: [2]int{2000, 1500},
+			end:    RoundTimeOver,
+			rounds: 2,
+			wins:   [2]int{2, 0},
+			winner: 0,
+		},
+		{
+			name:   "time over with equal health",
+			rules:  func(r *Rules) { r.RoundFrames = 60 },
+			health: [2]int{1000, 1000},
+			end:    RoundTimeOver,
+			rounds: 2,
+			wins:   [2]int{2, 2},
+			winner: -1,
+		},
+		{
+			name:   "double KO both win",
+			rules:  func(r *Rules) { r.Draw = DrawBothWin },
+			health: [2]int{300, 300},
+			script: trade,
+			end:    RoundDoubleKO,
+			rounds: 2,
+			wins:   [2]int{2, 2},
+			winner: -1,
+		},
+		{
+			name:   "double KO replayed",
+			rules:  func(r *Rules) { r.Draw = DrawReplay; r.MaxRounds = 3 },
+			health: [2]int{300, 300},
+			script: trade,
+			end:    RoundDoubleKO,
+			rounds: 3,
+			wins:   [2]int{0, 0},
+			winner: -1,
+		},
+		{
+			name:   "KO",
+			health: [2]int{2000, 300},
+			script: trade,
+			end:    RoundKO,
+			rounds: 2,
+			wins:   [2]int{2, 0},
+			winner: 0,
+		},
+		{
+			name:   "best of three",
+			rules:  func(r *Rules) { r.RoundsToWin = 3; r.MaxRounds = 3; r.RoundFrames = 60 },
+			health: [2]int{1000, 1500},
+			end:    RoundTimeOver,
+			rounds: 3,
+			wins:   [2]int{0, 3},
+			winner: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			script, err := input.ReadScript(strings.NewReader(c.script))
+			if err != nil {
+				t.Fatal(err)
+			}
+			game := newTestGame(t)
+			if c.rules != nil {
+				c.rules(&game.Rules)
+			}
+			var start [2]int
+			round, fightFrame := 0, -1
+
+			for frame := 0; !game.Match.Over(); frame++ {
+				if frame > 20000 {
+					t.Fatalf("the match didn't end, round %d in phase %v", game.Match.Round, game.Match.Phase)
+				}
+				inputs := [2]input.GameInput{input.Right | input.A, input.Left | input.A}
+				if fightFrame >= 0 && frame-fightFrame < len(script) {
+					inputs = script[frame-fightFrame]
+				}
+				game.Update(inputs)
+
+				if game.Match.Round != round {
+					// every round starts from the starting positions with full health
+					round, fightFrame = game.Match.Round, -1
+					for i, char := range game.Characters {
+						sm := char.StateMachine
+						if sm.HP != char.MaxHealth() {
+							t.Fatalf("round %d starts with %d health for player %d", round, sm.HP, i+1)
+						}
+						if round == 1 {
+							start[i] = sm.Position.X.Int()
+						} else if x := sm.Position.X.Int(); x != start[i] {
+							t.Fatalf("round %d starts with player %d at %d, want %d", round, i+1, x, start[i])
+						}
+					}
+				}
+				switch phase := game.Match.Phase; {
+				case phase == MatchIntro:
+					// inputs are dropped during the intro, nobody walks
+					for i, char := range game.Characters {
+						if x := char.StateMachine.Position.X.Int(); x != start[i] {
+							t.Fatalf("player %d moved to %d during the intro", i+1, x)
+						}
+					}
+				case phase == MatchFight && fightFrame < 0:
+					for i, char := range game.Characters {
+						char.StateMachine.HP = c.health[i]
+					}
+					fightFrame = frame + 1
+				}
+			}
+
+			match := game.Match
+			if match.RoundEnd != c.end || match.Round != c.rounds || match.Wins != c.wins || match.Winner != c.winner {
+				t.Fatalf("got %v after %d rounds, wins %v, winner %d, want %v after %d rounds, wins %v, winner %d",
+					match.RoundEnd, match.Round, match.Wins, match.Winner, c.end, c.rounds, c.wins, c.winner)
+			}
+		})
+	}
+}
+
+func TestKOSlowMotion(t *testing.T) {
+	game := newTestGame(t)
+	game.Rules.IntroFrames = 0
+	game.Update([2]input.GameInput{})
+	game.Characters[1].StateMachine.HP = 0
+	game.Update([2]input.GameInput{})
+	if game.Match.Phase != MatchKO {
+		t.Fatalf("phase %v, want KO", game.Match.Phase)
+	}
+
+	// the players advance on KOTimeScale percent of the frames
+	advanced := 0
+	for range 100 {
+		if game.stepsTime(0) {
+			advanced++
+		}
+	}
+	if advanced != game.Rules.KOTimeScale {
+		t.Fatalf("advanced %d of 100 frames, want %d", advanced, game.Rules.KOTimeScale)
+	}
+}
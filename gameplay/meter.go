@@ -0,0 +1,42 @@
+package gameplay
+
+import "fgengine/animation"
+
+// gainMeter adds meter to the player, capped by the character's max meter
+func (g *GameState) gainMeter(playerIndex, amount int) {
+	if amount <= 0 {
+		return
+	}
+	g.Meter[playerIndex] = min(g.Meter[playerIndex]+amount, g.Characters[playerIndex].MaxMeter())
+}
+
+// gainMeterFromDamage gives the defender the share of the damage its character converts into meter
+func (g *GameState) gainMeterFromDamage(playerIndex, damage int) {
+	g.gainMeter(playerIndex, damage*g.Characters[playerIndex].Meter.DamageTakenPercent/100)
+}
+
+// meterCost returns the meter needed to start an animation
+func meterCost(sm *animation.StateMachine, animationName string) int {
+	anim, exists := sm.AnimPlayer.Animations[animationName]
+	if !exists || anim == nil {
+		return 0
+	}
+	return anim.MeterCost()
+}
+
+// canAfford returns true if the player has the meter to start the animation
+func (g *GameState) canAfford(playerIndex int, animationName string) bool {
+	if animationName == "" {
+		return true
+	}
+	return meterCost(g.Characters[playerIndex].StateMachine, animationName) <= g.Meter[playerIndex]
+}
+
+// applyFrameMeter gives the whiff gain of a frame that just started, the cost of the whole animation is spent when it
+// starts so loops don't pay it again
+func (g *GameState) applyFrameMeter(playerIndex int, player *animation.AnimationPlayer, frameData *animation.FrameData, animationStarted bool) {
+	if animationStarted {
+		g.Meter[playerIndex] = max(g.Meter[playerIndex]-player.ActiveAnimation.MeterCost(), 0)
+	}
+	g.gainMeter(playerIndex, frameData.MeterGainOnWhiff)
+}
@@ -0,0 +1,49 @@
+package gameplay
+
+import (
+	"fgengine/input"
+	"strings"
+	"testing"
+)
+
+// TestMeterCostSpentOnce holds a looping dash that costs meter on two of its frames, the whole cost is spent once
+func TestMeterCostSpentOnce(t *testing.T) {
+	game := newTestGame(t)
+	game.Rules.IntroFrames = 0
+	dash := game.Characters[0].StateMachine.AnimPlayer.Animations["66"]
+	if len(dash.FrameData) < 2 {
+		t.Fatal("the sparring dash needs two frames")
+	}
+	dash.FrameData[0].MeterCost = 100
+	dash.FrameData[1].MeterCost = 50
+	game.Meter[0] = 1000
+
+	frames, err := input.ReadScript(strings.NewReader(settle + "1 6 5\n1 5 5\n10 6 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, inputs := range frames {
+		game.Update(inputs)
+	}
+	if name := game.Characters[0].StateMachine.AnimPlayer.ActiveAnimationName(); name != "66" {
+		t.Fatalf("playing %q, the dash didn't start", name)
+	}
+	if game.Meter[0] != 850 {
+		t.Fatalf("meter is %d after the dash looped, want 850", game.Meter[0])
+	}
+
+	// without the meter for both frames the dash doesn't start
+	game = newTestGame(t)
+	game.Rules.IntroFrames = 0
+	game.Characters[0].StateMachine.AnimPlayer.Animations["66"].FrameData[1].MeterCost = 200
+	game.Meter[0] = 150
+	for _, inputs := range frames {
+		game.Update(inputs)
+	}
+	if name := game.Characters[0].StateMachine.AnimPlayer.ActiveAnimationName(); name == "66" {
+		t.Fatal("the dash started without enough meter")
+	}
+	if game.Meter[0] != 150 {
+		t.Fatalf("meter is %d, nothing should have been spent", game.Meter[0])
+	}
+}
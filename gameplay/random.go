@@ -0,0 +1,31 @@
+package gameplay
+
+const defaultSeed uint64 = 0x9e3779b97f4a7c15
+
+// RNG is the random source of the simulation, it lives in the game state so snapshots and replays reproduce every roll.
+type RNG struct {
+	State uint64
+}
+
+func NewRNG(seed uint64) RNG {
+	if seed == 0 {
+		seed = defaultSeed
+	}
+	return RNG{State: seed}
+}
+
+// Next returns the next value of a xorshift64* sequence
+func (r *RNG) Next() uint64 {
+	if r.State == 0 {
+		r.State = defaultSeed
+	}
+	r.State ^= r.State >> 12
+	r.State ^= r.State << 25
+	r.State ^= r.State >> 27
+	return r.State * 0x2545f4914f6cdd1d
+}
+
+// Intn returns a value in [0, n), n must be positive
+func (r *RNG) Intn(n int) int {
+	return int(r.Next() % uint64(n))
+}
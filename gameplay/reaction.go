@@ -0,0 +1,161 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/types"
+	"slices"
+)
+
+const (
+	proximityGuardDistance = 40 // how close an active hitbox must be for holding back to enter the block pose
+	armorFlashFrames       = 8  // how long a character flashes after armor absorbs a hit
+)
+
+// startHurtReaction puts the defender in hitstun and picks the hurt animation that fits its current state.
+func startHurtReaction(sm *animation.StateMachine, launched bool) {
+	sm.Reaction = animation.ReactionHurt
+	sm.Blockstun = 0
+
+	switch {
+	case launched || sm.IsAirborne():
+		sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimHurtAir, animation.AnimHurtHigh)
+	case sm.Crouching:
+		sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimHurtLow, animation.AnimHurtHigh)
+	default:
+		sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimHurtHigh)
+	}
+}
+
+// startBlockReaction puts the defender in blockstun with the block animation for its stance.
+func startBlockReaction(sm *animation.StateMachine, crouching bool) {
+	sm.Reaction = animation.ReactionBlock
+	sm.Hitstun = 0
+	setBlockAnimation(sm, crouching)
+}
+
+// setBlockAnimation plays the block pose, it doesn't restart the animation if the pose is already playing.
+func setBlockAnimation(sm *animation.StateMachine, crouching bool) {
+	names := []string{animation.AnimBlockHigh}
+	switch {
+	case sm.IsAirborne():
+		names = []string{animation.AnimBlockAir, animation.AnimBlockHigh}
+	case crouching:
+		names = []string{animation.AnimBlockLow, animation.AnimBlockHigh}
+	}
+
+	for _, name := range names {
+		if _, exists := sm.AnimPlayer.Animations[name]; !exists {
+			continue
+		}
+		if sm.AnimPlayer.ActiveAnimationName() != name {
+			sm.AnimPlayer.SetAnimation(name)
+		}
+		return
+	}
+}
+
+// tickReaction counts down the stun timers at the start of the frame, so a player leaving stun can act on the same frame.
+func tickReaction(sm *animation.StateMachine) {
+	if sm.Hitstun > 0 {
+		sm.Hitstun--
+	}
+	if sm.Blockstun > 0 {
+		sm.Blockstun--
+	}
+	if sm.ThrowInvulnerable > 0 {
+		sm.ThrowInvulnerable--
+	}
+	if sm.FlashFrames > 0 {
+		sm.FlashFrames--
+	}
+	if sm.ClashCancelFrames > 0 {
+		sm.ClashCancelFrames--
+	}
+
+	switch sm.Reaction {
+	case animation.ReactionHurt:
+		// airborne hitstun lasts until landing
+		if sm.Hitstun == 0 && !sm.IsAirborne() {
+			recoverFromReaction(sm)
+		}
+	case animation.ReactionBlock:
+		if sm.Blockstun == 0 {
+			recoverFromReaction(sm)
+		}
+	case animation.ReactionKnockdown:
+		if sm.KnockdownTimer > 0 {
+			sm.KnockdownTimer--
+		}
+		if sm.KnockdownTimer == 0 {
+			startWakeup(sm)
+		}
+	case animation.ReactionWakeup:
+		if !slices.Contains(wakeupAnimations(sm.WakeupOption), sm.AnimPlayer.ActiveAnimationName()) || sm.AnimPlayer.IsFinished() {
+			recoverFromReaction(sm)
+		}
+	}
+}
+
+// updateReactionPostPhysics handles the reaction transitions that depend on physics, like landing from an airborne hit.
+func (g *GameState) updateReactionPostPhysics(ctx playerFrameContext) {
+	sm := ctx.stateMachine
+	landedThisFrame := ctx.wasAirborne && !sm.IsAirborne()
+
+	if sm.Reaction != animation.ReactionHurt {
+		return
+	}
+
+	bounceOffWall(sm)
+	if landedThisFrame && !bounceOffGround(sm) {
+		g.startKnockdown(ctx.playerIndex)
+	}
+}
+
+// startKnockdown lays the character on the ground, hard knockdowns last longer and don't allow a rise choice.
+func (g *GameState) startKnockdown(playerIndex int) {
+	sm := g.Characters[playerIndex].StateMachine
+	sm.Reaction = animation.ReactionKnockdown
+	sm.Hitstun = 0
+	sm.WallBounce = false
+	sm.WakeupOption = animation.WakeupNormal
+	sm.KnockdownTimer = g.Rules.SoftKnockdownFrames
+	if sm.HardKnockdown {
+		sm.KnockdownTimer = g.Rules.HardKnockdownFrames
+	}
+	sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimKnockdown, animation.AnimHurtAir, animation.AnimHurtHigh)
+
+	g.Knockdowns[playerIndex] = KnockdownInfo{
+		Hard:       sm.HardKnockdown,
+		StartFrame: g.Frame,
+	}
+	g.Knockdowns[playerIndex].updateDuration(sm, g.Frame)
+}
+
+// startWakeup plays the wakeup animation of the chosen rise, the character is invulnerable until it ends.
+func startWakeup(sm *animation.StateMachine) {
+	sm.Reaction = animation.ReactionWakeup
+	if sm.WakeupOption == animation.WakeupBackRise {
+		sm.Velocity.X = types.IntToFixed(-facingDirection(sm) * backRiseVelocity)
+	}
+	if !sm.AnimPlayer.SetFirstAvailableAnimation(wakeupAnimations(sm.WakeupOption)...) {
+		recoverFromReaction(sm)
+	}
+}
+
+func recoverFromReaction(sm *animation.StateMachine) {
+	sm.Reaction = animation.ReactionNone
+	sm.Hitstun = 0
+	sm.Blockstun = 0
+	sm.KnockdownTimer = 0
+	sm.GroundBounce = false
+	sm.WallBounce = false
+	sm.HardKnockdown = false
+	sm.WakeupOption = animation.WakeupNormal
+	sm.ThrowInvulnerable = throwInvulnerabilityFrames
+
+	if sm.IsAirborne() {
+		sm.AnimPlayer.SetFirstAvailableAnimation("fall", "idle")
+		return
+	}
+	sm.AnimPlayer.SetFirstAvailableAnimation("idle")
+}
@@ -0,0 +1,132 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultRulesPath = "./assets/rules.yaml"
+
+// TradeRule decides what happens when both players hit each other on the same frame
+type TradeRule uint8
+
+const (
+	TradeBothHit  TradeRule = iota // both players take the hit
+	TradePriority                  // the attack with higher FrameData.Priority wins, equal priority trades
+)
+
+// DrawRule decides the score of a round ending in a draw, by double KO or time over with equal health
+type DrawRule uint8
+
+const (
+	DrawBothWin DrawRule = iota // both players get the round
+	DrawReplay                  // nobody scores, the round is played again
+)
+
+// Rules holds the game wide combat rules, so different designs can be tested without touching the resolution code
+type Rules struct {
+	Trade TradeRule `yaml:"trade"`
+
+	Clashes           bool `yaml:"clashes"`           // hitbox-on-hitbox contact cancels both attacks
+	ClashHitstop      int  `yaml:"clashHitstop"`      // frames both players freeze on a clash
+	ClashCancelWindow int  `yaml:"clashCancelWindow"` // frames after the clash hitstop where any move can be canceled into
+	ClashPushback     int  `yaml:"clashPushback"`
+
+	CounterHit    CounterModifiers `yaml:"counterHit"`
+	PunishCounter CounterModifiers `yaml:"punishCounter"`
+
+	ComboScaling         []int `yaml:"comboScaling"`         // damage percent by hit number in the combo, the last value repeats
+	MinimumDamagePercent int   `yaml:"minimumDamagePercent"` // scaling never goes below this percent of the unscaled damage
+	ComboGapTolerance    int   `yaml:"comboGapTolerance"`    // frames the defender can be free before the counter resets, hits in that gap start a new combo, the previous one is kept as dropped
+
+	JuggleLimit int `yaml:"juggleLimit"` // juggle points a combo can spend on airborne or knocked down defenders
+
+	ReversalWindow int `yaml:"reversalWindow"` // frames after leaving stun or wakeup where a move counts as a reversal
+
+	SoftKnockdownFrames int `yaml:"softKnockdownFrames"` // frames on the ground before waking up when no rise is chosen
+	HardKnockdownFrames int `yaml:"hardKnockdownFrames"`
+	QuickRiseFrames     int `yaml:"quickRiseFrames"` // frames on the ground left after choosing quick or back rise
+
+	HitstopLight  int `yaml:"hitstopLight"` // hitstop of attacks without their own FrameData.Hitstop, by strength
+	HitstopMedium int `yaml:"hitstopMedium"`
+	HitstopHeavy  int `yaml:"hitstopHeavy"`
+
+	RoundFrames    int      `yaml:"roundFrames"` // 0 disables the timer
+	RoundsToWin    int      `yaml:"roundsToWin"`
+	MaxRounds      int      `yaml:"maxRounds"` // the match is decided by wins after this many rounds, 0 means no limit
+	Draw           DrawRule `yaml:"draw"`
+	IntroFrames    int      `yaml:"introFrames"` // players can't act before the fight starts
+	KOFrames       int      `yaml:"koFrames"`    // slow motion after a KO
+	KOTimeScale    int      `yaml:"koTimeScale"` // percent of frames advanced during the KO slow motion
+	RoundEndFrames int      `yaml:"roundEndFrames"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		Trade:             TradeBothHit,
+		Clashes:           true,
+		ClashHitstop:      12,
+		ClashCancelWindow: 10,
+		ClashPushback:     6,
+		CounterHit: CounterModifiers{
+			DamagePercent:  110,
+			HitstunPercent: 120,
+			UntechPercent:  150,
+		},
+		PunishCounter: CounterModifiers{
+			DamagePercent:  120,
+			HitstunPercent: 150,
+			UntechPercent:  200,
+		},
+		ComboScaling:         []int{100, 100, 80, 70, 60, 50, 40, 30},
+		MinimumDamagePercent: 10,
+		ComboGapTolerance:    30,
+		JuggleLimit:          10,
+		ReversalWindow:       3,
+		SoftKnockdownFrames:  30,
+		HardKnockdownFrames:  50,
+		QuickRiseFrames:      8,
+		HitstopLight:         8,
+		HitstopMedium:        11,
+		HitstopHeavy:         14,
+		RoundFrames:          99 * 60,
+		RoundsToWin:          2,
+		MaxRounds:            5,
+		Draw:                 DrawBothWin,
+		IntroFrames:          120,
+		KOFrames:             90,
+		KOTimeScale:          40,
+		RoundEndFrames:       120,
+	}
+}
+
+// LoadRules reads a rules file, values missing from the file keep their defaults
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read rules: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return DefaultRules(), fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	return rules, nil
+}
+
+// hitstop returns the freeze frames of an attack, its own value or the default of its strength
+func (r *Rules) hitstop(frameData *animation.FrameData) int {
+	if frameData.Hitstop > 0 {
+		return frameData.Hitstop
+	}
+	switch frameData.Strength {
+	case animation.StrengthMedium:
+		return r.HitstopMedium
+	case animation.StrengthHeavy:
+		return r.HitstopHeavy
+	default:
+		return r.HitstopLight
+	}
+}
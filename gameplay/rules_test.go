@@ -0,0 +1,39 @@
+package gameplay
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadRules(t *testing.T) {
+	// the shipped file spells out the defaults
+	rules, err := LoadRules(filepath.Join("..", DefaultRulesPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rules, DefaultRules()) {
+		t.Fatalf("got %+v, want the defaults %+v", rules, DefaultRules())
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("trade: 1\nclashes: false\nroundsToWin: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultRules()
+	want.Trade = TradePriority
+	want.Clashes = false
+	want.RoundsToWin = 3
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("got %+v, want %+v", rules, want)
+	}
+
+	if _, err := LoadRules(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("a missing file loaded")
+	}
+}
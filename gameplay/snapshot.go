@@ -0,0 +1,182 @@
+package gameplay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fgengine/animation"
+	"fgengine/entity"
+	"fgengine/input"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// Snapshot is a copy of the whole simulation, it shares nothing with the game state it was saved from.
+// Character data like animations and rules are left out, animations are referenced by name and found again on load.
+// HitEvents and Clashed only describe the last update and aren't saved, they are rebuilt by the next one.
+type Snapshot struct {
+	Players   [2]PlayerSnapshot
+	Entities  []EntitySnapshot
+	InputHist [2][]input.GameInput
+
+	Meter         [2]int
+	Combos        [2]Combo
+	DroppedCombos [2]Combo
+	Throw         ThrowSnapshot
+	Knockdowns    [2]KnockdownInfo
+	Frame         int
+	SuperFlash    SuperFlash
+	Match         Match
+	RNG           RNG
+
+	BufferedIntents [2]input.Intent
+	ReversalFrames  [2]int
+	ReversalMove    [2]bool
+	TimeCarry       [2]int
+}
+
+type PlayerSnapshot struct {
+	StateMachine animation.StateMachine // AnimPlayer is always nil, see Animation
+	Animation    animation.PlayerState
+}
+
+type EntitySnapshot struct {
+	Entity    entity.Entity // AnimPlayer is always nil, see Animation
+	Animation animation.PlayerState
+}
+
+type ThrowSnapshot struct {
+	Active    bool
+	Attacker  int
+	Grab      animation.FrameRef
+	TechTimer int
+}
+
+// Save copies the simulation, the snapshot stays valid however the game state changes after it.
+func (g *GameState) Save() Snapshot {
+	s := Snapshot{
+		Meter:         g.Meter,
+		Combos:        g.Combos,
+		DroppedCombos: g.DroppedCombos,
+		Knockdowns:    g.Knockdowns,
+		Frame:         g.Frame,
+		SuperFlash:    g.SuperFlash,
+		Match:         g.Match,
+		RNG:           g.RNG,
+		Throw: ThrowSnapshot{
+			Active:    g.throw.active,
+			Attacker:  g.throw.attacker,
+			Grab:      g.throw.grab,
+			TechTimer: g.throw.techTimer,
+		},
+		BufferedIntents: g.bufferedIntents,
+		ReversalFrames:  g.reversalFrames,
+		ReversalMove:    g.reversalMove,
+		TimeCarry:       g.timeCarry,
+	}
+
+	for i, char := range g.Characters {
+		sm := *char.StateMachine
+		sm.AnimPlayer = nil
+		s.Players[i] = PlayerSnapshot{StateMachine: sm, Animation: char.StateMachine.AnimPlayer.SaveState()}
+		s.InputHist[i] = slices.Clone(g.inputHist[i])
+	}
+
+	s.Entities = make([]EntitySnapshot, 0, len(g.Entities))
+	for _, e := range g.Entities {
+		copied := *e
+		copied.AnimPlayer = nil
+		s.Entities = append(s.Entities, EntitySnapshot{Entity: copied, Animation: e.AnimPlayer.SaveState()})
+	}
+	return s
+}
+
+// Load puts the simulation back in a saved state, the same snapshot can be loaded any number of times.
+// The characters must be the ones the snapshot was saved with.
+func (g *GameState) Load(s *Snapshot) {
+	g.Meter = s.Meter
+	g.Combos = s.Combos
+	g.DroppedCombos = s.DroppedCombos
+	g.Knockdowns = s.Knockdowns
+	g.Frame = s.Frame
+	g.SuperFlash = s.SuperFlash
+	g.Match = s.Match
+	g.RNG = s.RNG
+	g.throw = throwSequence{
+		active:    s.Throw.Active,
+		attacker:  s.Throw.Attacker,
+		grab:      s.Throw.Grab,
+		techTimer: s.Throw.TechTimer,
+	}
+	g.bufferedIntents = s.BufferedIntents
+	g.reversalFrames = s.ReversalFrames
+	g.reversalMove = s.ReversalMove
+	g.timeCarry = s.TimeCarry
+	g.HitEvents = nil
+	g.Clashed = false
+
+	for i, char := range g.Characters {
+		player := char.StateMachine.AnimPlayer
+		*char.StateMachine = s.Players[i].StateMachine
+		char.StateMachine.AnimPlayer = player
+		player.LoadState(s.Players[i].Animation)
+		g.inputHist[i] = slices.Clone(s.InputHist[i])
+	}
+
+	g.Entities = make([]*entity.Entity, 0, len(s.Entities))
+	for _, saved := range s.Entities {
+		e := saved.Entity
+		// entities share the animations of their owner
+		e.AnimPlayer = new(animation.AnimationPlayer{
+			Animations: g.Characters[e.Owner].StateMachine.AnimPlayer.Animations,
+		})
+		e.AnimPlayer.LoadState(saved.Animation)
+		g.Entities = append(g.Entities, &e)
+	}
+}
+
+// snapshotData has the fields of Snapshot without its methods, so gob doesn't call MarshalBinary recursively
+type snapshotData Snapshot
+
+// MarshalBinary encodes the snapshot, equal snapshots always give the same bytes
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode((*snapshotData)(s)); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	// decoding into a used snapshot would keep the fields that were zero when encoded
+	*s = Snapshot{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode((*snapshotData)(s)); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return nil
+}
+
+// Checksum hashes the encoded snapshot, two machines simulating the same frames get the same checksum
+func (s *Snapshot) Checksum() (uint64, error) {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	hash := fnv.New64a()
+	hash.Write(data)
+	return hash.Sum64(), nil
+}
+
+// DataChecksum hashes what the simulation depends on besides the state and the inputs, the character files and the
+// rules. Games with different data checksums can't stay in sync.
+func (g *GameState) DataChecksum() (uint64, error) {
+	hash := fnv.New64a()
+	for _, char := range g.Characters {
+		hash.Write(binary.LittleEndian.AppendUint64(nil, char.DataChecksum))
+	}
+	if err := gob.NewEncoder(hash).Encode(g.Rules); err != nil {
+		return 0, fmt.Errorf("failed to encode rules: %w", err)
+	}
+	return hash.Sum64(), nil
+}
@@ -0,0 +1,135 @@
+package gameplay
+
+import (
+	"fgengine/input"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SyncTest runs the game normally, but after every frame it loads the state of CheckDistance frames ago and simulates
+// those frames again. Rollback netplay does the same, any difference with the first run is a desync waiting to happen.
+type SyncTest struct {
+	Game          *GameState
+	CheckDistance int
+
+	frames []syncFrame // the last CheckDistance frames, oldest first
+}
+
+type syncFrame struct {
+	before   Snapshot
+	inputs   [2]input.GameInput
+	after    Snapshot
+	checksum uint64 // of after
+}
+
+// SyncError reports the first frame that gave a different result when simulated again
+type SyncError struct {
+	Frame int    // value of GameState.Frame before the update
+	Diff  string // first field that diverged, with the value of both runs
+}
+
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("sync test failed on frame %d: %s", e.Frame, e.Diff)
+}
+
+func NewSyncTest(game *GameState, checkDistance int) *SyncTest {
+	return &SyncTest{Game: game, CheckDistance: max(checkDistance, 1)}
+}
+
+// Update advances the game by one frame then checks the last CheckDistance frames, the game is left in the resimulated state.
+func (t *SyncTest) Update(inputs [2]input.GameInput) error {
+	before := t.Game.Save()
+	t.Game.Update(inputs)
+	after := t.Game.Save()
+	checksum, err := after.Checksum()
+	if err != nil {
+		return err
+	}
+
+	t.frames = append(t.frames, syncFrame{before: before, inputs: inputs, after: after, checksum: checksum})
+	if len(t.frames) > t.CheckDistance {
+		t.frames = slices.Delete(t.frames, 0, 1)
+	}
+	if len(t.frames) < t.CheckDistance {
+		return nil
+	}
+
+	t.Game.Load(&t.frames[0].before)
+	for _, frame := range t.frames {
+		t.Game.Update(frame.inputs)
+		resimulated := t.Game.Save()
+		checksum, err := resimulated.Checksum()
+		if err != nil {
+			return err
+		}
+		if checksum != frame.checksum {
+			return &SyncError{Frame: frame.before.Frame, Diff: firstDifference(&frame.after, &resimulated)}
+		}
+	}
+	return nil
+}
+
+// firstDifference describes the first field that differs between two snapshots, like "Players[1].StateMachine.HP: 900 != 850"
+func firstDifference(expected, got *Snapshot) string {
+	if diff, ok := diffValues("", reflect.ValueOf(*expected), reflect.ValueOf(*got)); ok {
+		return diff
+	}
+	// same values with different encodings, a field diffValues doesn't walk like a map
+	return "snapshots differ only in their encoding"
+}
+
+func diffValues(path string, a, b reflect.Value) (string, bool) {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := range a.NumField() {
+			field := strings.TrimPrefix(path+"."+a.Type().Field(i).Name, ".")
+			if diff, ok := diffValues(field, a.Field(i), b.Field(i)); ok {
+				return diff, true
+			}
+		}
+		return "", false
+	case reflect.Array, reflect.Slice:
+		if a.Len() != b.Len() {
+			return fmt.Sprintf("%s: length %d != %d", path, a.Len(), b.Len()), true
+		}
+		for i := range a.Len() {
+			if diff, ok := diffValues(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i)); ok {
+				return diff, true
+			}
+		}
+		return "", false
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return fmt.Sprintf("%s: %v != %v", path, a, b), true
+			}
+			return "", false
+		}
+		return diffValues(path, a.Elem(), b.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			return fmt.Sprintf("%s: %d != %d", path, a.Int(), b.Int()), true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if a.Uint() != b.Uint() {
+			return fmt.Sprintf("%s: %d != %d", path, a.Uint(), b.Uint()), true
+		}
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			return fmt.Sprintf("%s: %t != %t", path, a.Bool(), b.Bool()), true
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			return fmt.Sprintf("%s: %q != %q", path, a.String(), b.String()), true
+		}
+	case reflect.Float32, reflect.Float64:
+		// bits so NaN equals itself and -0 differs from 0, like in the encoded snapshot
+		if math.Float64bits(a.Float()) != math.Float64bits(b.Float()) {
+			return fmt.Sprintf("%s: %v != %v", path, a.Float(), b.Float()), true
+		}
+	}
+	return "", false
+}
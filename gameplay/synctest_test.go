@@ -0,0 +1,91 @@
+package gameplay
+
+import (
+	"fgengine/character"
+	"fgengine/input"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const syncTestDistance = 8
+
+func TestSyncRecordedInputs(t *testing.T) {
+	recordings, err := filepath.Glob("testdata/synctest/*.inputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordings) == 0 {
+		t.Fatal("no recorded inputs in testdata/synctest")
+	}
+
+	for _, path := range recordings {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			frames, err := loadRecordedInputs(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			game := newTestGame(t)
+			syncTest := NewSyncTest(game, syncTestDistance)
+			for _, inputs := range frames {
+				if err := syncTest.Update(inputs); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffValuesFloats(t *testing.T) {
+	type value struct{ F float64 }
+	tests := []struct {
+		name string
+		a, b float64
+		diff bool
+	}{
+		{"equal", 1.5, 1.5, false},
+		{"different", 1.5, 2, true},
+		{"NaN", math.NaN(), math.NaN(), false},
+		{"signed zero", 0, math.Copysign(0, -1), true},
+	}
+	for _, tt := range tests {
+		_, diff := diffValues("", reflect.ValueOf(value{tt.a}), reflect.ValueOf(value{tt.b}))
+		if diff != tt.diff {
+			t.Errorf("%s: got difference %t, want %t", tt.name, diff, tt.diff)
+		}
+	}
+}
+
+// newTestGame loads the sparring character on both sides, it has boxes so the recordings can hit, block and throw
+func newTestGame(t *testing.T) *GameState {
+	t.Helper()
+
+	var characters [2]*character.Character
+	for i := range characters {
+		var err error
+		characters[i], err = character.LoadCharacterFile("testdata/characters/sparring.yaml", i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &GameState{Characters: characters, Rules: DefaultRules()}
+}
+
+// loadRecordedInputs reads an input script, see input.ReadScript
+func loadRecordedInputs(path string) ([][2]input.GameInput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	frames, err := input.ReadScript(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return frames, nil
+}
@@ -0,0 +1,155 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/input"
+	"fgengine/types"
+)
+
+const (
+	throwInvulnerabilityFrames = 6                 // throws whiff for this long after leaving stun or waking up
+	throwTechPushback          = 8                 // horizontal speed both players get when a throw is teched
+	throwTechButtons           = input.A | input.B // pressed together by the defender to break a throw
+)
+
+// throwSequence pairs two players while a throw is being performed, the defender can tech until techTimer runs out.
+type throwSequence struct {
+	active    bool
+	attacker  int
+	grab      animation.FrameRef // attacker frame that grabbed, damage and knockback are applied from it
+	techTimer int
+}
+
+// resolveThrows connects new throws, it runs before strikes so a grabbed player's attack never comes out.
+func (g *GameState) resolveThrows() {
+	if g.throw.active {
+		return
+	}
+
+	p1 := g.Characters[0].StateMachine
+	p2 := g.Characters[1].StateMachine
+
+	p1Throws := checkThrow(p1, p2)
+	p2Throws := checkThrow(p2, p1)
+
+	switch {
+	case p1Throws && p2Throws:
+		// simultaneous throws always break
+		p1.AnimPlayer.AttackConnected = true
+		p2.AnimPlayer.AttackConnected = true
+		techThrow(p1, p2)
+	case p1Throws:
+		g.startThrow(0, 1)
+	case p2Throws:
+		g.startThrow(1, 0)
+	}
+}
+
+// startThrow locks both players into the paired throw animations.
+func (g *GameState) startThrow(attackerIndex, defenderIndex int) {
+	attacker := g.Characters[attackerIndex].StateMachine
+	defender := g.Characters[defenderIndex].StateMachine
+
+	frameData := attacker.AnimPlayer.ActiveFrameData()
+	attacker.AnimPlayer.AttackConnected = true
+	g.throw = throwSequence{
+		active:    true,
+		attacker:  attackerIndex,
+		grab:      attacker.AnimPlayer.ActiveFrameRef(),
+		techTimer: frameData.ThrowTechWindow,
+	}
+
+	attacker.Velocity = types.FixedVector2{}
+	if frameData.ThrowAnimation != "" {
+		attacker.AnimPlayer.SetFirstAvailableAnimation(frameData.ThrowAnimation)
+	}
+
+	thrownAnimation := frameData.ThrownAnimation
+	if thrownAnimation == "" {
+		thrownAnimation = animation.AnimThrown
+	}
+	defender.Reaction = animation.ReactionThrown
+	defender.Hitstun = 0
+	defender.Blockstun = 0
+	defender.ProximityGuard = false
+	defender.Velocity = types.FixedVector2{}
+	defender.AnimPlayer.SetFirstAvailableAnimation(thrownAnimation, animation.AnimHurtHigh)
+}
+
+// updateThrow holds the defender in front of the attacker, checks for a tech and applies the throw when the tech window ends.
+// The sequence only advances on frames where both players run, a super flash or the KO slow motion pause it.
+func (g *GameState) updateThrow(running [2]bool) {
+	if !g.throw.active {
+		return
+	}
+
+	attackerIndex := g.throw.attacker
+	defenderIndex := 1 - attackerIndex
+	attacker := g.Characters[attackerIndex].StateMachine
+	defender := g.Characters[defenderIndex].StateMachine
+	frameData := attacker.AnimPlayer.FrameDataAt(g.throw.grab)
+	if frameData == nil {
+		// the grab frame is gone, like after loading a snapshot made with other character data
+		g.throw = throwSequence{}
+		defender.Reaction = animation.ReactionNone
+		defender.AnimPlayer.SetFirstAvailableAnimation("idle")
+		return
+	}
+
+	offset := types.FloatToFixed(frameData.ThrowOffsetX)
+	if attacker.IsFacingLeft == animation.Left {
+		offset = -offset
+	}
+	defender.Position = types.FixedVector2{
+		X: attacker.Position.X + offset,
+		Y: attacker.Position.Y,
+	}
+	defender.Velocity = types.FixedVector2{}
+	if !running[attackerIndex] || !running[defenderIndex] {
+		return
+	}
+
+	if g.throw.techTimer > 0 && g.techPressed(defenderIndex) {
+		techThrow(attacker, defender)
+		g.throw = throwSequence{}
+		return
+	}
+
+	if g.throw.techTimer > 0 {
+		g.throw.techTimer--
+		return
+	}
+
+	g.throw = throwSequence{}
+	damage := g.registerComboHit(attackerIndex, frameData.Damage, frameData.StarterScaling)
+	applyHit(defender, frameData, facingDirection(attacker), damage, frameData.Hitstun)
+	g.gainMeter(attackerIndex, frameData.MeterGainOnHit)
+	g.gainMeterFromDamage(defenderIndex, damage)
+	g.HitEvents = append(g.HitEvents, HitEvent{
+		Attacker:     attackerIndex,
+		Defender:     defenderIndex,
+		FrameData:    frameData,
+		ContactPoint: defender.Position,
+		Throw:        true,
+	})
+}
+
+// techPressed returns true on the frame the defender completes the tech buttons
+func (g *GameState) techPressed(playerIndex int) bool {
+	history := g.inputHist[playerIndex]
+	if len(history) < 2 {
+		return false
+	}
+	current := history[len(history)-1]
+	previous := history[len(history)-2]
+	return current&throwTechButtons == throwTechButtons && previous&throwTechButtons != throwTechButtons
+}
+
+// techThrow breaks the throw, both players recover and are pushed apart.
+func techThrow(a, b *animation.StateMachine) {
+	for _, sm := range []*animation.StateMachine{a, b} {
+		sm.Reaction = animation.ReactionNone
+		sm.Velocity = types.FixedVector2{X: types.IntToFixed(-facingDirection(sm) * throwTechPushback)}
+		sm.AnimPlayer.SetFirstAvailableAnimation(animation.AnimThrowTech, "idle")
+	}
+}
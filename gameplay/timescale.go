@@ -0,0 +1,49 @@
+package gameplay
+
+const normalTimeScale = 100 // percent of frames advanced at normal speed
+
+// SuperFlash freezes everything but the player that started a super, the length comes from FrameData.SuperFlash
+type SuperFlash struct {
+	Owner      int
+	FramesLeft int // frames still frozen, counting the current one
+}
+
+func (s *SuperFlash) Active() bool {
+	return s.FramesLeft > 0
+}
+
+// timeScale returns the percent of frames a player and its entities advance, the opponent of a super flash is frozen
+// and everything slows down after a KO.
+func (g *GameState) timeScale(playerIndex int) int {
+	if g.SuperFlash.Active() && g.SuperFlash.Owner != playerIndex {
+		return 0
+	}
+	if g.Match.Phase == MatchKO {
+		return g.Rules.KOTimeScale
+	}
+	return normalTimeScale
+}
+
+// stepsTime accumulates the time scale of the player and returns true if it advances this frame
+func (g *GameState) stepsTime(playerIndex int) bool {
+	g.timeCarry[playerIndex] += g.timeScale(playerIndex)
+	if g.timeCarry[playerIndex] < normalTimeScale {
+		return false
+	}
+	g.timeCarry[playerIndex] -= normalTimeScale
+	return true
+}
+
+// startSuperFlash freezes the opponent from the next frame, a flash already running is replaced
+func (g *GameState) startSuperFlash(playerIndex, frames int) {
+	g.SuperFlash = SuperFlash{
+		Owner:      playerIndex,
+		FramesLeft: frames,
+	}
+}
+
+func (g *GameState) updateSuperFlash() {
+	if g.SuperFlash.FramesLeft > 0 {
+		g.SuperFlash.FramesLeft--
+	}
+}
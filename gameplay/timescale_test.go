@@ -0,0 +1,46 @@
+package gameplay
+
+import (
+	"fgengine/input"
+	"testing"
+)
+
+func TestSuperFlashFreezesOpponent(t *testing.T) {
+	game := newTestGame(t)
+	game.Rules.IntroFrames = 0
+	for range 30 {
+		game.Update([2]input.GameInput{})
+	}
+
+	const flashFrames = 20
+	owner := game.Characters[0].StateMachine
+	opponent := game.Characters[1].StateMachine
+	walk := [2]input.GameInput{input.Right, input.Left}
+	game.Update(walk)
+	game.startSuperFlash(0, flashFrames)
+
+	ownerX, opponentX := owner.Position.X, opponent.Position.X
+	opponentFrame, opponentTimeLeft := opponent.AnimPlayer.FrameIndex, opponent.AnimPlayer.FrameTimeLeft
+	timer := game.Match.Timer
+	for range flashFrames {
+		game.Update(walk)
+		if opponent.Position.X != opponentX || opponent.AnimPlayer.FrameIndex != opponentFrame ||
+			opponent.AnimPlayer.FrameTimeLeft != opponentTimeLeft {
+			t.Fatalf("the opponent moved during the flash on frame %d", game.Frame)
+		}
+	}
+	if owner.Position.X <= ownerX {
+		t.Errorf("the owner stayed at %v during the flash", owner.Position.X)
+	}
+	if game.Match.Timer != timer {
+		t.Errorf("the timer went from %d to %d during the flash", timer, game.Match.Timer)
+	}
+	if game.SuperFlash.Active() {
+		t.Fatalf("the flash is still active with %d frames left", game.SuperFlash.FramesLeft)
+	}
+
+	game.Update(walk)
+	if opponent.Position.X == opponentX {
+		t.Error("the opponent is still frozen after the flash")
+	}
+}
@@ -0,0 +1,39 @@
+package gameplay
+
+import "fgengine/types"
+
+// FrameTrace summarizes the game state after a frame for tools and regression tests, positions stay in fixed point
+// so traces compare exactly
+type FrameTrace struct {
+	Frame    int            `json:"frame"`
+	Players  [2]PlayerTrace `json:"players"`
+	Entities int            `json:"entities"`
+	Meter    [2]int         `json:"meter"`
+}
+
+type PlayerTrace struct {
+	Position   types.FixedVector2 `json:"position"`
+	Velocity   types.FixedVector2 `json:"velocity"`
+	Animation  string             `json:"animation"`
+	FrameIndex int                `json:"frameIndex"`
+	HP         int                `json:"hp"`
+}
+
+func (g *GameState) Trace() FrameTrace {
+	trace := FrameTrace{
+		Frame:    g.Frame,
+		Entities: len(g.Entities),
+		Meter:    g.Meter,
+	}
+	for i, char := range g.Characters {
+		sm := char.StateMachine
+		trace.Players[i] = PlayerTrace{
+			Position:   sm.Position,
+			Velocity:   sm.Velocity,
+			Animation:  sm.AnimPlayer.ActiveAnimationName(),
+			FrameIndex: sm.AnimPlayer.FrameIndex,
+			HP:         sm.HP,
+		}
+	}
+	return trace
+}
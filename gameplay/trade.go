@@ -0,0 +1,39 @@
+package gameplay
+
+import (
+	"fgengine/animation"
+	"fgengine/types"
+)
+
+// resolveTrades applies the trade rule when both players hit each other on the same frame, the events that lose are dropped.
+func resolveTrades(events []HitEvent, rule TradeRule) []HitEvent {
+	if len(events) != 2 || events[0].Attacker == events[1].Attacker {
+		return events
+	}
+
+	if rule == TradePriority {
+		first, second := events[0].FrameData.Priority, events[1].FrameData.Priority
+		if first > second {
+			return events[:1]
+		}
+		if second > first {
+			return events[1:]
+		}
+	}
+
+	for i := range events {
+		events[i].Trade = true
+	}
+	return events
+}
+
+// resolveClash spends both attacks, freezes both players and opens a window where any move can be canceled into.
+func (g *GameState) resolveClash(p1, p2 *animation.StateMachine) {
+	for _, sm := range []*animation.StateMachine{p1, p2} {
+		sm.AnimPlayer.AttackConnected = true
+		sm.Hitstop = g.Rules.ClashHitstop
+		sm.ClashCancelFrames = g.Rules.ClashCancelWindow
+		sm.Velocity.X = types.IntToFixed(-facingDirection(sm) * g.Rules.ClashPushback)
+	}
+	g.Clashed = true
+}
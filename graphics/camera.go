@@ -11,6 +11,19 @@ type Camera struct {
 	Viewport        types.Rect
 	WorldBoundsLock bool
 	Scaling         float64
+
+	Script *CameraScript // overrides the tracked position while set
+}
+
+// CameraScript takes the camera to a target and zoom for a number of frames, used by cinematics like super flashes.
+type CameraScript struct {
+	Target  types.Vector2
+	Zoom    float64 // scaling once the ease in is over
+	Frames  int     // frames on the script before easing out, the ease in included
+	EaseIn  int     // frames to move from the tracked position to the target
+	EaseOut int     // frames to move back to the tracked position after Frames, the camera goes back to tracking then
+
+	elapsed int
 }
 
 // Makes a camera centered in the world
@@ -27,7 +40,17 @@ func NewCamera() *Camera {
 	}
 }
 
+// PlayScript starts a scripted camera move, it replaces any script already playing
+func (c *Camera) PlayScript(script CameraScript) {
+	script.elapsed = 0
+	c.Script = &script
+}
+
 func (c *Camera) UpdatePosition(targetPos types.Vector2) {
+	if c.Script != nil {
+		targetPos = c.updateScript(targetPos)
+	}
+
 	// Center viewport around target position
 	c.Viewport.X = targetPos.X - c.Viewport.W/2
 	c.Viewport.Y = targetPos.Y - c.Viewport.H/2
@@ -36,6 +59,31 @@ func (c *Camera) UpdatePosition(targetPos types.Vector2) {
 	}
 }
 
+// updateScript advances the script and returns the position to center on, easing from the tracked position to the
+// target and back the same way
+func (c *Camera) updateScript(trackedPos types.Vector2) types.Vector2 {
+	script := c.Script
+	script.elapsed++
+	if script.elapsed > script.Frames+script.EaseOut {
+		c.Script = nil
+		c.Scaling = 1
+		return trackedPos
+	}
+
+	progress := 1.0
+	if script.EaseIn > 0 && script.elapsed < script.EaseIn {
+		progress = float64(script.elapsed) / float64(script.EaseIn)
+	}
+	if script.elapsed > script.Frames {
+		progress = min(progress, float64(script.Frames+script.EaseOut-script.elapsed)/float64(script.EaseOut))
+	}
+	c.Scaling = 1 + (script.Zoom-1)*progress
+	return types.Vector2{
+		X: trackedPos.X + (script.Target.X-trackedPos.X)*progress,
+		Y: trackedPos.Y + (script.Target.Y-trackedPos.Y)*progress,
+	}
+}
+
 func (c *Camera) SetPosition(pos types.Vector2) {
 	c.Viewport.X = pos.X
 	c.Viewport.Y = pos.Y
@@ -80,26 +128,17 @@ func (c *Camera) ScreenToWorld(screenPos types.Vector2) types.Vector2 {
 	}
 }
 
-func layoutMatchesCamSize(camera *Camera) bool {
-	return (float64(constants.Camera.W) == camera.Viewport.W && float64(constants.Camera.H) == camera.Viewport.H)
-}
-
 func CameraTransform(options *ebiten.DrawImageOptions, camera *Camera, entityScale types.Vector2, screenPos types.Vector2) {
 	options.GeoM.Scale(entityScale.X, entityScale.Y)
+	options.GeoM.Translate(screenPos.X, screenPos.Y)
 
-	centerX := camera.Viewport.W / 2
-	centerY := camera.Viewport.H / 2
-
+	// zoom around the center of the viewport, after placing the image so everything scales together
 	if camera.Scaling != 0 && camera.Scaling != 1 {
+		centerX := camera.Viewport.W / 2
+		centerY := camera.Viewport.H / 2
+
 		options.GeoM.Translate(-centerX, -centerY)
 		options.GeoM.Scale(camera.Scaling, camera.Scaling)
 		options.GeoM.Translate(centerX, centerY)
-
-		// scale again?
-		if !layoutMatchesCamSize(camera) {
-			options.GeoM.Scale(entityScale.X, entityScale.Y)
-		}
 	}
-
-	options.GeoM.Translate(screenPos.X, screenPos.Y)
 }
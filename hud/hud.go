@@ -0,0 +1,220 @@
+package hud
+
+import (
+	"fgengine/gameplay"
+	"fgengine/graphics"
+	"fgengine/types"
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	debugCharWidth  = 6 // width of a character printed by ebitenutil.DebugPrintAt
+	framesPerSecond = 60
+)
+
+// HUD draws the fight information over the game, it keeps the state of its animations like the red health drain
+type HUD struct {
+	Layout Layout
+
+	drainHP    [2]int // health shown in red, drains down to the real health once the combo ends
+	drainDelay [2]int
+	comboHits  [2]int // hits of each player's combo on the last update
+	callouts   [2][]callout
+}
+
+type callout struct {
+	text       string
+	framesLeft int
+	combo      bool // combo counters replace each other instead of stacking
+}
+
+func New(layout Layout) *HUD {
+	return &HUD{Layout: layout}
+}
+
+// Update follows the game state, it must be called once after every GameState.Update
+func (h *HUD) Update(g *gameplay.GameState) {
+	for i, char := range g.Characters {
+		hp := char.StateMachine.HP
+
+		// health going up means a new round
+		if hp > h.drainHP[i] {
+			h.drainHP[i] = hp
+		}
+		switch {
+		case g.Combos[1-i].Active():
+			h.drainDelay[i] = h.Layout.DrainDelay
+		case h.drainDelay[i] > 0:
+			h.drainDelay[i]--
+		default:
+			h.drainHP[i] = max(h.drainHP[i]-h.Layout.DrainSpeed, hp)
+		}
+	}
+
+	for side := range h.callouts {
+		h.callouts[side] = ageCallouts(h.callouts[side])
+	}
+
+	for _, event := range g.HitEvents {
+		if event.Blocked || event.Whiffed || event.Armored {
+			continue
+		}
+		switch event.Counter {
+		case gameplay.CounterHit:
+			h.addCallout(event.Attacker, "COUNTER", false)
+		case gameplay.CounterPunish:
+			h.addCallout(event.Attacker, "PUNISH COUNTER", false)
+		}
+		if event.Reversal {
+			h.addCallout(event.Attacker, "REVERSAL", false)
+		}
+	}
+
+	for i, combo := range g.Combos {
+		if combo.Hits >= 2 && combo.Hits != h.comboHits[i] {
+			h.addCallout(i, fmt.Sprintf("%d HITS", combo.Hits), true)
+		}
+		h.comboHits[i] = combo.Hits
+	}
+}
+
+func ageCallouts(callouts []callout) []callout {
+	kept := callouts[:0]
+	for _, c := range callouts {
+		c.framesLeft--
+		if c.framesLeft > 0 {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+func (h *HUD) addCallout(side int, text string, combo bool) {
+	entry := callout{text: text, framesLeft: h.Layout.Callout.Frames, combo: combo}
+	if combo {
+		for i := range h.callouts[side] {
+			if h.callouts[side][i].combo {
+				h.callouts[side][i] = entry
+				return
+			}
+		}
+	}
+	h.callouts[side] = append(h.callouts[side], entry)
+}
+
+func (h *HUD) Draw(screen *ebiten.Image, g *gameplay.GameState) {
+	screenW := float64(screen.Bounds().Dx())
+
+	for i, char := range g.Characters {
+		side := sideLayout{index: i, screenW: screenW}
+		maxHealth := char.MaxHealth()
+
+		h.drawBar(screen, h.Layout.HealthBar, side, char.StateMachine.HP, h.drainHP[i], maxHealth)
+		h.drawBar(screen, h.Layout.MeterBar, side, g.Meter[i], 0, char.MaxMeter())
+		if h.Layout.MeterPerBar > 0 {
+			stocks := fmt.Sprint(g.Meter[i] / h.Layout.MeterPerBar)
+			meterRect := side.rect(h.Layout.MeterBar.Rect)
+			stockX := meterRect.Right() + debugCharWidth
+			if i == 1 {
+				stockX = meterRect.X - 2*debugCharWidth
+			}
+			ebitenutil.DebugPrintAt(screen, stocks, int(stockX), int(meterRect.Y)-4)
+		}
+
+		side.print(screen, char.Name, h.Layout.Name.Position)
+		h.drawPips(screen, side, g.Match.Wins[i], g.Rules.RoundsToWin)
+
+		for line, c := range h.callouts[i] {
+			position := h.Layout.Callout.Position
+			position.Y += float64(line) * h.Layout.Callout.LineSpacing
+			side.print(screen, c.text, position)
+		}
+	}
+
+	timer := "--"
+	if g.Rules.RoundFrames > 0 {
+		timer = fmt.Sprintf("%02d", (g.Match.Timer+framesPerSecond-1)/framesPerSecond)
+	}
+	timerPos := h.Layout.Timer.Position
+	ebitenutil.DebugPrintAt(screen, timer, int(timerPos.X)-len(timer)*debugCharWidth/2, int(timerPos.Y))
+}
+
+// drawBar draws the back, the drain and the fill of a gauge, the drain is skipped when it isn't above the value
+func (h *HUD) drawBar(screen *ebiten.Image, bar Bar, side sideLayout, value, drain, maxValue int) {
+	rect := side.rect(bar.Rect)
+	fillRect(screen, rect, bar.Back)
+	if maxValue <= 0 {
+		return
+	}
+
+	// player one's bars are anchored on the right when they are anchored near the center
+	anchorRight := side.index == 0
+	if bar.FromOuterEdge {
+		anchorRight = !anchorRight
+	}
+	portion := func(amount int) types.Rect {
+		width := rect.W * float64(min(max(amount, 0), maxValue)) / float64(maxValue)
+		portion := types.Rect{X: rect.X, Y: rect.Y, W: width, H: rect.H}
+		if anchorRight {
+			portion.X = rect.Right() - width
+		}
+		return portion
+	}
+
+	if drain > value {
+		fillRect(screen, portion(drain), bar.Drain)
+	}
+	fillRect(screen, portion(value), bar.Fill)
+
+	if bar.Image != "" {
+		op := &ebiten.DrawImageOptions{}
+		if side.index == 1 {
+			op.GeoM.Scale(-1, 1)
+			op.GeoM.Translate(rect.W, 0)
+		}
+		op.GeoM.Translate(rect.X, rect.Y)
+		screen.DrawImage(graphics.LoadImage(bar.Image), op)
+	}
+}
+
+func (h *HUD) drawPips(screen *ebiten.Image, side sideLayout, wins, roundsToWin int) {
+	pips := h.Layout.RoundPips
+	for pip := range roundsToWin {
+		col := pips.Empty
+		if pip < wins {
+			col = pips.Won
+		}
+		rect := types.Rect{X: pips.Position.X - float64(pip)*pips.Spacing, Y: pips.Position.Y, W: pips.Size, H: pips.Size}
+		fillRect(screen, side.rect(rect), col)
+	}
+}
+
+// sideLayout mirrors player one's layout for player two
+type sideLayout struct {
+	index   int
+	screenW float64
+}
+
+func (s sideLayout) rect(r types.Rect) types.Rect {
+	if s.index == 1 {
+		r.X = s.screenW - r.X - r.W
+	}
+	return r
+}
+
+func (s sideLayout) print(screen *ebiten.Image, text string, position types.Vector2) {
+	x := position.X
+	if s.index == 1 {
+		x = s.screenW - x - float64(len(text)*debugCharWidth)
+	}
+	ebitenutil.DebugPrintAt(screen, text, int(x), int(position.Y))
+}
+
+func fillRect(screen *ebiten.Image, r types.Rect, col color.Color) {
+	vector.FillRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), col, false)
+}
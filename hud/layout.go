@@ -0,0 +1,102 @@
+package hud
+
+import (
+	"fgengine/types"
+	"fmt"
+	"image/color"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultLayoutPath = "./assets/hud/layout.yaml"
+
+// Layout places every HUD element, positions are for player one and mirrored for player two
+type Layout struct {
+	HealthBar Bar      `yaml:"healthBar"`
+	MeterBar  Bar      `yaml:"meterBar"`
+	Name      Label    `yaml:"name"`
+	RoundPips Pips     `yaml:"roundPips"`
+	Timer     Label    `yaml:"timer"`   // centered on the screen, not mirrored
+	Callout   Callouts `yaml:"callout"` // combo, counter hit and reversal texts
+
+	DrainDelay  int `yaml:"drainDelay"`  // frames the red damage stays after the combo ends
+	DrainSpeed  int `yaml:"drainSpeed"`  // health drained from the red damage every frame after the delay
+	MeterPerBar int `yaml:"meterPerBar"` // meter of one stock, the meter gauge shows the stock count
+}
+
+// Bar is a gauge, its fill is anchored at the edge near the center of the screen unless FromOuterEdge is set
+type Bar struct {
+	Rect          types.Rect `yaml:"rect"`
+	Fill          color.RGBA `yaml:"fill"`
+	Back          color.RGBA `yaml:"back"`
+	Drain         color.RGBA `yaml:"drain,omitempty"` // health lost in the current combo, drawn behind the fill
+	Image         string     `yaml:"image,omitempty"` // frame drawn over the bar
+	FromOuterEdge bool       `yaml:"fromOuterEdge,omitempty"`
+}
+
+type Label struct {
+	Position types.Vector2 `yaml:"position"`
+}
+
+// Pips are the round win markers, one for each round needed to win
+type Pips struct {
+	Position types.Vector2 `yaml:"position"`
+	Size     float64       `yaml:"size"`
+	Spacing  float64       `yaml:"spacing"`
+	Won      color.RGBA    `yaml:"won"`
+	Empty    color.RGBA    `yaml:"empty"`
+}
+
+type Callouts struct {
+	Position    types.Vector2 `yaml:"position"`
+	LineSpacing float64       `yaml:"lineSpacing"`
+	Frames      int           `yaml:"frames"` // how long a callout stays on screen
+}
+
+func DefaultLayout() Layout {
+	return Layout{
+		HealthBar: Bar{
+			Rect:  types.Rect{X: 20, Y: 16, W: 250, H: 14},
+			Fill:  color.RGBA{R: 240, G: 200, B: 40, A: 255},
+			Back:  color.RGBA{R: 40, G: 40, B: 40, A: 255},
+			Drain: color.RGBA{R: 200, G: 30, B: 30, A: 255},
+		},
+		MeterBar: Bar{
+			Rect:          types.Rect{X: 20, Y: 336, W: 150, H: 8},
+			Fill:          color.RGBA{R: 60, G: 160, B: 240, A: 255},
+			Back:          color.RGBA{R: 40, G: 40, B: 40, A: 255},
+			FromOuterEdge: true,
+		},
+		Name: Label{Position: types.Vector2{X: 20, Y: 32}},
+		RoundPips: Pips{
+			Position: types.Vector2{X: 258, Y: 34},
+			Size:     8,
+			Spacing:  12,
+			Won:      color.RGBA{R: 240, G: 60, B: 60, A: 255},
+			Empty:    color.RGBA{R: 60, G: 60, B: 60, A: 255},
+		},
+		Timer: Label{Position: types.Vector2{X: 320, Y: 18}},
+		Callout: Callouts{
+			Position:    types.Vector2{X: 20, Y: 100},
+			LineSpacing: 14,
+			Frames:      60,
+		},
+		DrainDelay:  30,
+		DrainSpeed:  40,
+		MeterPerBar: 1000,
+	}
+}
+
+// LoadLayout reads a layout file, values missing from the file keep their defaults
+func LoadLayout(path string) (Layout, error) {
+	layout := DefaultLayout()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return layout, fmt.Errorf("failed to read hud layout: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return DefaultLayout(), fmt.Errorf("failed to unmarshal hud layout: %w", err)
+	}
+	return layout, nil
+}
@@ -0,0 +1,293 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultCommandBuffer      = 15 // frames a motion can take, from its first direction to its last step
+	DefaultChargeFrames       = 30 // frames a charge is held, like the 4 of [4]6A or the A of [A]
+	DefaultSimultaneousFrames = 3  // frames between the presses of buttons pressed together, like A+B
+)
+
+const (
+	directionMask = Up | Down | Left | Right
+	buttonMask    = A | B | C | D
+)
+
+// Command is an input of a character and the animation it starts. Commands are written in numpad notation
+// with the directions seen from a character facing right:
+//
+//	236+A    quarter circle forward then A, the + is optional
+//	[4]6B    hold back for Charge frames, then forward and B
+//	22C      down twice then C
+//	41236    half circle forward, a motion without a button
+//	632146   half circle back from forward
+//	236~A    quarter circle forward then release A, negative edge
+//	A+B      A and B pressed within Window frames of each other
+//	2[C]     hold C for Charge frames and release it while holding down
+type Command struct {
+	Input     string `yaml:"input"`
+	Animation string `yaml:"animation"`
+	Buffer    int    `yaml:"buffer,omitempty"` // 0 uses DefaultCommandBuffer
+	Charge    int    `yaml:"charge,omitempty"` // 0 uses DefaultChargeFrames
+	Window    int    `yaml:"window,omitempty"` // 0 uses DefaultSimultaneousFrames
+	// Strict makes every direction match exactly. Without it 2 also matches 1 and 3, and diagonals of the motion
+	// can be skipped, so 2 then 6 is a quarter circle.
+	Strict bool `yaml:"strict,omitempty"`
+
+	steps   []motionStep
+	buttons GameInput
+	trigger buttonTrigger
+}
+
+type motionStep struct {
+	direction GameInput
+	charge    bool // held for the command's charge time
+}
+
+type buttonTrigger int
+
+const (
+	triggerPress   buttonTrigger = iota // the buttons are pressed, together within the window
+	triggerRelease                      // the button is released, ~A
+	triggerHold                         // the button is released after being held for the charge time, [A]
+)
+
+// Parse reads Input, it must be called before the command is matched
+func (c *Command) Parse() error {
+	c.steps = nil
+	c.buttons = NoInput
+	c.trigger = triggerPress
+
+	notation := strings.ReplaceAll(c.Input, " ", "")
+	buttonCount := 0
+	for i := 0; i < len(notation); i++ {
+		char := notation[i]
+		switch {
+		case char == '[' && i+2 < len(notation) && notation[i+2] == ']':
+			if button := notationButtons[rune(notation[i+1])]; button != NoInput {
+				c.buttons |= button
+				c.trigger = triggerHold
+				buttonCount++
+			} else if direction, ok := numpadDirections[notation[i+1]]; ok && direction != NoInput && c.buttons == NoInput {
+				c.steps = append(c.steps, motionStep{direction: direction, charge: true})
+			} else {
+				return fmt.Errorf("invalid command %q: can't charge %q", c.Input, notation[i+1])
+			}
+			i += 2
+		case char == '[':
+			return fmt.Errorf("invalid command %q: a charge is written like [4] or [A]", c.Input)
+		case numpadDirections[char] != NoInput || char == '5':
+			if c.buttons != NoInput {
+				return fmt.Errorf("invalid command %q: directions must come before the buttons", c.Input)
+			}
+			c.steps = append(c.steps, motionStep{direction: numpadDirections[char]})
+		case char == '+':
+			if i+1 >= len(notation) || (notationButtons[rune(notation[i+1])] == NoInput && notation[i+1] != '~' && notation[i+1] != '[') {
+				return fmt.Errorf("invalid command %q: + must be followed by a button", c.Input)
+			}
+		case char == '~':
+			if i+1 >= len(notation) || notationButtons[rune(notation[i+1])] == NoInput {
+				return fmt.Errorf("invalid command %q: ~ must be followed by a button", c.Input)
+			}
+			c.buttons |= notationButtons[rune(notation[i+1])]
+			c.trigger = triggerRelease
+			buttonCount++
+			i++
+		case notationButtons[rune(char)] != NoInput:
+			if c.buttons&notationButtons[rune(char)] != 0 {
+				return fmt.Errorf("invalid command %q: %q is used twice", c.Input, char)
+			}
+			c.buttons |= notationButtons[rune(char)]
+			buttonCount++
+		default:
+			return fmt.Errorf("invalid command %q: unexpected %q", c.Input, char)
+		}
+	}
+
+	if len(c.steps) == 0 && c.buttons == NoInput {
+		return fmt.Errorf("invalid command %q: a command needs a motion or a button", c.Input)
+	}
+	if c.trigger != triggerPress && buttonCount > 1 {
+		return fmt.Errorf("invalid command %q: only a single button can be released or held", c.Input)
+	}
+	if c.Animation == "" {
+		return fmt.Errorf("command %q has no animation", c.Input)
+	}
+	return nil
+}
+
+// HasButton returns true for commands finished by a button, like specials. Motions without a button, like dashes,
+// keep matching while the last direction is held, up to the buffer.
+func (c *Command) HasButton() bool {
+	return c.buttons != NoInput
+}
+
+// HistoryLength returns the frames of input history the command needs to be matched
+func (c *Command) HistoryLength() int {
+	frames := c.buffer() + 1
+	for _, step := range c.steps {
+		if step.charge {
+			frames += c.charge()
+			break
+		}
+	}
+	switch c.trigger {
+	case triggerPress:
+		frames = max(frames, c.window()+1)
+	case triggerHold:
+		frames = max(frames, c.charge()+1)
+	}
+	// the previous frame is matched too, so held buttons don't trigger twice
+	return frames + 1
+}
+
+// Matches returns true when the input history ends with the command, the newest input last. Directions of the history
+// must be relative to the facing of the character, Right being forward.
+func (c *Command) Matches(inputs []GameInput) bool {
+	if len(inputs) == 0 || (len(c.steps) == 0 && c.buttons == NoInput) {
+		return false
+	}
+	last := len(inputs) - 1
+	if c.buttons != NoInput && !c.matchesButtons(inputs) {
+		return false
+	}
+	if len(c.steps) == 0 {
+		return true
+	}
+
+	// the buffer ends with the button, or with the first frame of the last direction so holding it keeps a run going
+	// for up to the buffer
+	end := last
+	if c.buttons == NoInput {
+		direction := c.steps[len(c.steps)-1].direction
+		if !c.matchesDirection(direction, inputs[last]) {
+			return false
+		}
+		for end > 0 && c.matchesDirection(direction, inputs[end-1]) {
+			end--
+		}
+		if last-end > c.buffer() {
+			return false
+		}
+	}
+	return c.matchSteps(len(c.steps)-1, inputs, last, max(end-c.buffer(), 0))
+}
+
+// matchesButtons checks the buttons on the last frame of the history
+func (c *Command) matchesButtons(inputs []GameInput) bool {
+	last := len(inputs) - 1
+	switch c.trigger {
+	case triggerRelease:
+		return last > 0 && JustReleased(inputs[last], inputs[last-1], c.buttons)
+	case triggerHold:
+		if last < c.charge() || inputs[last].IsPressed(c.buttons) {
+			return false
+		}
+		for frame := last - c.charge(); frame < last; frame++ {
+			if !inputs[frame].IsPressed(c.buttons) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if inputs[last]&c.buttons != c.buttons {
+		return false
+	}
+	// every button must have been pressed in the window, not held from before it
+	for _, button := range []GameInput{A, B, C, D} {
+		if c.buttons&button == 0 {
+			continue
+		}
+		pressed := last
+		for pressed > 0 && inputs[pressed-1].IsPressed(button) {
+			pressed--
+		}
+		if pressed <= last-c.window() {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSteps looks for steps[0:step+1] in inputs[earliest:cursor+1], going back from the last step. The latest frame
+// matching a step always leaves the most room for the steps before it, so only skipped diagonals need a second try.
+func (c *Command) matchSteps(step int, inputs []GameInput, cursor, earliest int) bool {
+	if step < 0 {
+		return true
+	}
+	s := c.steps[step]
+
+	for frame := cursor; frame >= earliest; frame-- {
+		if !c.matchesDirection(s.direction, inputs[frame]) {
+			continue
+		}
+		if s.charge && !c.charged(s.direction, inputs, frame) {
+			continue
+		}
+
+		next := frame - 1
+		// the same direction twice, like 66, must be released in between
+		if step > 0 && c.steps[step-1].direction == s.direction {
+			for next >= earliest && c.matchesDirection(s.direction, inputs[next]) {
+				next--
+			}
+		}
+		if c.matchSteps(step-1, inputs, next, earliest) {
+			return true
+		}
+		break
+	}
+
+	if !c.Strict && !s.charge && isDiagonal(s.direction) {
+		return c.matchSteps(step-1, inputs, cursor, earliest)
+	}
+	return false
+}
+
+// charged returns true when the direction was held for the charge time up to the frame. Charges only look at the
+// history, so one held direction charges every command using it: holding 1 charges both [4]6 and [2]8, and the charge
+// keeps building during other moves.
+func (c *Command) charged(direction GameInput, inputs []GameInput, frame int) bool {
+	held := 0
+	for ; frame >= 0 && c.matchesDirection(direction, inputs[frame]); frame-- {
+		held++
+	}
+	return held >= c.charge()
+}
+
+func (c *Command) matchesDirection(want, in GameInput) bool {
+	in &= directionMask
+	if c.Strict || want == NoInput || isDiagonal(want) {
+		return in == want
+	}
+	return in&want == want
+}
+
+func (c *Command) buffer() int {
+	if c.Buffer <= 0 {
+		return DefaultCommandBuffer
+	}
+	return c.Buffer
+}
+
+func (c *Command) charge() int {
+	if c.Charge <= 0 {
+		return DefaultChargeFrames
+	}
+	return c.Charge
+}
+
+func (c *Command) window() int {
+	if c.Window <= 0 {
+		return DefaultSimultaneousFrames
+	}
+	return c.Window
+}
+
+func isDiagonal(direction GameInput) bool {
+	return direction&(Up|Down) != 0 && direction&(Left|Right) != 0
+}
@@ -0,0 +1,155 @@
+package input
+
+import "testing"
+
+// history builds an input history from notation, like history(t, "2", "3", "6A")
+func history(t *testing.T, notation ...string) []GameInput {
+	t.Helper()
+	inputs := make([]GameInput, len(notation))
+	for i, n := range notation {
+		in, err := ParseNotation(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[i] = in
+	}
+	return inputs
+}
+
+func repeat(notation string, frames int) []string {
+	inputs := make([]string, frames)
+	for i := range inputs {
+		inputs[i] = notation
+	}
+	return inputs
+}
+
+func TestParseCommand(t *testing.T) {
+	for _, input := range []string{"236+A", "236A", "[4]6B", "22C", "41236", "632146", "[2]8 D", "A", "A+B", "236AB", "236~A", "2[C]", "[4]6+[B]"} {
+		c := Command{Input: input, Animation: "special"}
+		if err := c.Parse(); err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+	}
+	for _, input := range []string{"", "236+", "236AA", "2A6", "[4", "[5]6A", "236X", "~", "~A+B", "[A][B]", "[A]6"} {
+		c := Command{Input: input, Animation: "special"}
+		if err := c.Parse(); err == nil {
+			t.Errorf("%q parsed, expected an error", input)
+		}
+	}
+}
+
+func TestCommandMatches(t *testing.T) {
+	cases := []struct {
+		input   string
+		strict  bool
+		history []string
+		want    bool
+	}{
+		{"236A", false, []string{"2", "3", "6A"}, true},
+		{"236A", false, []string{"2", "3", "6"}, false},
+		{"236A", false, []string{"2", "6A"}, true}, // diagonal skipped
+		{"236A", true, []string{"2", "6A"}, false},
+		{"236A", false, []string{"1", "3", "9A"}, true},
+		{"236A", true, []string{"1", "3", "9A"}, false},
+		{"236A", false, append(append([]string{"2", "3"}, repeat("6", 20)...), "6A"), false}, // past the buffer
+		{"22C", false, []string{"2", "5", "2C"}, true},
+		{"22C", false, []string{"2", "2", "2C"}, false}, // not released
+		{"41236", false, []string{"4", "1", "2", "3", "6"}, true},
+		{"632146", false, []string{"6", "3", "2", "1", "4", "6"}, true},
+		{"632146", false, []string{"6", "2", "4", "6"}, true},
+		{"[4]6B", false, append(repeat("4", 30), "6B"), true},
+		{"[4]6B", false, append(repeat("4", 29), "6B"), false},
+		{"[4]6B", false, append(repeat("1", 30), "5", "6B"), true}, // down back charges back
+		{"66", false, []string{"6", "5", "6"}, true},
+		{"66", false, append([]string{"6", "5"}, repeat("6", 16)...), true}, // held run, up to the buffer
+		{"66", false, append([]string{"6", "5"}, repeat("6", 17)...), false},
+		{"66", false, []string{"6", "6", "6"}, false},
+		{"[2]8D", false, append(repeat("1", 30), "7", "9D"), true},                     // 1 charges both down and back
+		{"[4]6B", false, append(append(repeat("1", 30), "2A", "2A", "2"), "6B"), true}, // charged during a normal
+
+		// negative edge
+		{"236~A", false, []string{"2A", "3A", "6A", "6"}, true},
+		{"236~A", false, []string{"2", "3", "6", "6"}, false},
+		{"236~A", false, []string{"2", "3", "6A"}, false},
+
+		// buttons together
+		{"A+B", false, []string{"5", "5AB"}, true},
+		{"A+B", false, []string{"5", "5A", "5A", "5AB"}, true},
+		{"A+B", false, []string{"5", "5A", "5A", "5A", "5AB"}, false}, // A pressed too early
+		{"A+B", false, []string{"5AB", "5AB", "5AB", "5AB"}, false},
+		{"236A+B", false, []string{"2", "3", "6A", "6AB"}, true},
+
+		// held buttons
+		{"[C]", false, append(append([]string{"5"}, repeat("5C", 30)...), "5"), true},
+		{"[C]", false, append(append([]string{"5"}, repeat("5C", 29)...), "5"), false},
+		{"2[C]", false, append(append([]string{"5"}, repeat("5C", 30)...), "2"), true},
+		{"2[C]", false, append(append([]string{"5"}, repeat("5C", 30)...), "6"), false},
+	}
+	for _, c := range cases {
+		command := Command{Input: c.input, Animation: "special", Strict: c.strict}
+		if err := command.Parse(); err != nil {
+			t.Fatal(err)
+		}
+		if got := command.Matches(history(t, c.history...)); got != c.want {
+			t.Errorf("%s strict=%v on %v: got %v, want %v", c.input, c.strict, c.history, got, c.want)
+		}
+	}
+}
+
+func TestCommandHistoryLength(t *testing.T) {
+	cases := []struct {
+		command Command
+		want    int
+	}{
+		{Command{Input: "236A"}, 17},
+		{Command{Input: "236A", Buffer: 8}, 10},
+		{Command{Input: "[4]6A"}, 47},
+		{Command{Input: "[4]6A", Charge: 60, Buffer: 10}, 72},
+		{Command{Input: "[A]", Charge: 90}, 92},
+		{Command{Input: "A+B", Buffer: 1, Window: 5}, 7},
+	}
+	for _, c := range cases {
+		c.command.Animation = "special"
+		if err := c.command.Parse(); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.command.HistoryLength(); got != c.want {
+			t.Errorf("%s: got %d frames, want %d", c.command.Input, got, c.want)
+		}
+	}
+}
+
+func TestCommandPriority(t *testing.T) {
+	commands := []Command{
+		{Input: "41236A", Animation: "super"},
+		{Input: "236A", Animation: "fireball"},
+	}
+	for i := range commands {
+		if err := commands[i].Parse(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := CheckCommands(commands, history(t, "4", "1", "2", "3", "6A")); got.Animation != "super" {
+		t.Errorf("got %q, the first matching command wins", got.Animation)
+	}
+	if got := CheckCommands(commands, history(t, "2", "3", "6A")); got.Animation != "fireball" {
+		t.Errorf("got %q, want fireball", got.Animation)
+	}
+	if got := CheckCommands(commands, history(t, "5", "5", "5A")); got != (Intent{Animation: "A", Button: true}) {
+		t.Errorf("got %+v, want the A normal", got)
+	}
+
+	// a button pressed during a held dash isn't swallowed by it
+	dash := []Command{{Input: "66", Animation: "dash"}}
+	if err := dash[0].Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if got := CheckCommands(dash, history(t, "6", "5", "6", "6", "6A")); got != (Intent{Animation: "A", Button: true}) {
+		t.Errorf("got %+v, want the A normal", got)
+	}
+	if got := CheckCommands(dash, history(t, "6", "5", "6", "6A", "6A")); got.Animation != "dash" {
+		t.Errorf("got %q, a held button doesn't stop the dash", got.Animation)
+	}
+}
@@ -60,13 +60,3 @@ func JustPressed(current, previous, button GameInput) bool {
 func JustReleased(current, previous, button GameInput) bool {
 	return !current.IsPressed(button) && previous.IsPressed(button)
 }
-
-func checkSOCD(input *GameInput) {
-	if input.IsPressed(Left) && input.IsPressed(Right) {
-		*input &^= (Left | Right)
-	}
-
-	if input.IsPressed(Up) && input.IsPressed(Down) {
-		*input &^= (Up | Down)
-	}
-}
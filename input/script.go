@@ -0,0 +1,81 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadScript reads scripted inputs for both players, one line per run of frames: "frames p1 p2", like "30 6 4" or "1 5A 2B".
+// Inputs are in numpad notation followed by the buttons, with screen directions for both players. Blank lines and lines
+// starting with # are skipped.
+func ReadScript(r io.Reader) ([][2]GameInput, error) {
+	var frames [][2]GameInput
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected frames and two inputs", line)
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("line %d: invalid frame count %q", line, fields[0])
+		}
+		var inputs [2]GameInput
+		for i := range inputs {
+			if inputs[i], err = ParseNotation(fields[i+1]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		for range count {
+			frames = append(frames, inputs)
+		}
+	}
+	return frames, scanner.Err()
+}
+
+var numpadDirections = map[byte]GameInput{
+	'1': Down | Left,
+	'2': Down,
+	'3': Down | Right,
+	'4': Left,
+	'5': NoInput,
+	'6': Right,
+	'7': Up | Left,
+	'8': Up,
+	'9': Up | Right,
+}
+
+var notationButtons = map[rune]GameInput{
+	'A': A,
+	'B': B,
+	'C': C,
+	'D': D,
+}
+
+// ParseNotation reads a numpad direction followed by buttons, like 5, 2B or 6AB
+func ParseNotation(notation string) (GameInput, error) {
+	if notation == "" {
+		return NoInput, fmt.Errorf("empty input notation")
+	}
+	direction, ok := numpadDirections[notation[0]]
+	if !ok {
+		return NoInput, fmt.Errorf("invalid direction in %q", notation)
+	}
+	in := direction
+	for _, r := range notation[1:] {
+		button, ok := notationButtons[r]
+		if !ok {
+			return NoInput, fmt.Errorf("invalid button %q in %q", r, notation)
+		}
+		in |= button
+	}
+	return in, nil
+}
@@ -1,131 +1,52 @@
 package input
 
-type InputSequence struct {
-	baseInput []GameInput // the main input sequence
-	buffer    int         // tolerance for buffering inputs
-	//alias []GameInput // simplified inputs
+// Intent is what a player asks for on a frame, the name of the animation to start
+type Intent struct {
+	Animation string
+	Button    bool // started by a button press, normals and specials
 }
 
-// isNonDirectionalInput checks if the input is a non-directional input (A, B, C, D)
-func isNonDirectionalInput(input GameInput) bool {
-	directionalInputs := Up | Down | Left | Right
-	return input != NoInput && (input&directionalInputs) == 0
-}
-
-var InputSequences = map[string]InputSequence{ // instead of strings, this should be an enum of common animation names
-	"66": {
-		baseInput: []GameInput{
-			Right, NoInput, Right, // instead of NoInput, this could be an "any non-directional input" placeholder that matches any of A, B, C, D
-		},
-		buffer: 10,
-	},
-	"236A": {
-		baseInput: []GameInput{
-			Down, Down | Right, Right, A,
-		},
-		buffer: 10,
-	},
-	"426A": {
-		baseInput: []GameInput{
-			Left, Down, Right, A,
-		},
-		buffer: 10,
-	},
-}
-
-func DetectInputSequence(inputSeq InputSequence, inputs []GameInput) bool {
-	if len(inputs) < len(inputSeq.baseInput) {
-		return false
-	}
-
-	sequenceLen := len(inputSeq.baseInput)
-	inputPos := len(inputs) - 1
-	buffer := inputSeq.buffer
-
-	// Work backwards through the required sequence
-	for seqIndex := sequenceLen - 1; seqIndex >= 0; seqIndex-- {
-		expectedInput := inputSeq.baseInput[seqIndex]
-		found := false
-		bufferUsed := 0
-
-		// Look backwards for the expected input within buffer range
-		for bufferUsed <= buffer && inputPos >= 0 {
-			currentInput := inputs[inputPos]
-
-			// for NoInput in the sequence, match any non-directional input
-			if (expectedInput == NoInput && isNonDirectionalInput(currentInput)) ||
-				currentInput == expectedInput ||
-				(expectedInput != NoInput && (currentInput&expectedInput) == expectedInput) {
-				found = true
-				inputPos-- // Move to the previous input for next sequence element
-				break
-			}
-
-			// Move backwards
-			inputPos--
-
-			// Only count non-neutral inputs against buffer
-			if currentInput != NoInput {
-				bufferUsed++
-			}
+// CheckCommands returns the intent of the first matching command, the order of the commands is their priority. Without a
+// matching command the last input is read alone, for normals and movement. A button pressed on the last frame skips the
+// motions without a button, so a held dash doesn't swallow the normal.
+func CheckCommands(commands []Command, inputs []GameInput) Intent {
+	last := inputs[len(inputs)-1]
+	pressed := last & buttonMask
+	if len(inputs) > 1 {
+		pressed &^= inputs[len(inputs)-2]
+	}
+	for i := range commands {
+		if pressed != NoInput && !commands[i].HasButton() {
+			continue
 		}
-
-		if !found {
-			return false
+		if commands[i].Matches(inputs) {
+			return Intent{Animation: commands[i].Animation, Button: commands[i].HasButton()}
 		}
 	}
 
-	return true
-}
-
-func CheckInputSequences(inputs []GameInput) string {
-	detected := []string{}
-	for name, seq := range InputSequences {
-		if DetectInputSequence(seq, inputs) {
-			detected = append(detected, name)
-		}
-	}
-	// Priority order first is higher:
-	// 1, Supers and Specials
-	// 2, normals
-	// 3, movements, walks and jumps
-
-	// if no special move was detected, check for single inputs to trigger normals and movements
-	if len(detected) == 0 {
-		return CheckSingleInput(inputs[len(inputs)-1])
-	}
-	return detected[len(detected)-1] // last one probably is the correct one...
+	return Intent{Animation: CheckSingleInput(last), Button: last&buttonMask != 0}
 }
 
 // CheckInputIntent returns a frame intent while preventing repeated triggers
 // for discrete button-based actions (normals/specials) held in input history.
-func CheckInputIntent(inputs []GameInput) string {
+func CheckInputIntent(commands []Command, inputs []GameInput) Intent {
 	if len(inputs) == 0 {
-		return ""
+		return Intent{}
 	}
 
-	current := CheckInputSequences(inputs)
-	if current == "" || !isDiscreteIntent(current) || len(inputs) == 1 {
+	current := CheckCommands(commands, inputs)
+	if current.Animation == "" || !current.Button || len(inputs) == 1 {
 		return current
 	}
 
-	previous := CheckInputSequences(inputs[:len(inputs)-1])
+	previous := CheckCommands(commands, inputs[:len(inputs)-1])
 	if previous == current {
-		return ""
+		return Intent{}
 	}
 
 	return current
 }
 
-func isDiscreteIntent(intent string) bool {
-	for _, r := range intent {
-		if r == 'A' || r == 'B' || r == 'C' || r == 'D' {
-			return true
-		}
-	}
-	return false
-}
-
 func CheckSingleInput(inputs GameInput) string {
 	// with priority order
 	if inputs.IsPressed(D) {
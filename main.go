@@ -2,14 +2,50 @@ package main
 
 import (
 	"fgengine/config"
+	"fgengine/netplay"
+	"fgengine/replay"
 	"fgengine/scene"
+	"flag"
+	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
+	// netplay on one machine: -local :7000 -remote 127.0.0.1:7001 -player 1, and -local :7001 -remote 127.0.0.1:7000 -player 2
+	localAddr := flag.String("local", ":7000", "udp address to listen on for netplay")
+	remoteAddr := flag.String("remote", "", "udp address of the other player, starts a netplay match when set")
+	player := flag.Int("player", 1, "side of the local player in netplay, 1 or 2")
+	inputDelay := flag.Int("delay", netplay.DefaultInputDelay, "netplay input delay in frames")
+	replayPath := flag.String("replay", "", "replay file to play back")
+	flag.IntVar(&scene.SyncTestDistance, "synctest", 0, "debug mode simulating the last n frames again every frame to find desyncs")
+	flag.Parse()
+
 	config.InitGameConfig()
-	if err := ebiten.RunGame(scene.NewSceneManager()); err != nil {
+
+	manager := scene.NewSceneManager()
+	if *replayPath != "" {
+		r, err := replay.Load(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if manager, err = scene.NewReplaySceneManager(r); err != nil {
+			log.Fatal(err)
+		}
+	} else if *remoteAddr != "" {
+		if *player != 1 && *player != 2 {
+			log.Fatalf("invalid netplay player %d, must be 1 or 2", *player)
+		}
+		transport, err := netplay.DialUDP(*localAddr, *remoteAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sessionConfig := netplay.DefaultConfig(*player - 1)
+		sessionConfig.InputDelay = *inputDelay
+		manager = scene.NewNetplaySceneManager(netplay.NewSession(transport, sessionConfig))
+	}
+
+	if err := ebiten.RunGame(manager); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,110 @@
+package netplay
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrTransportClosed = errors.New("transport closed")
+
+// LoopbackConfig describes the simulated connection of a loopback pair, both directions get the same conditions
+type LoopbackConfig struct {
+	Latency    time.Duration // one way
+	Jitter     time.Duration // added to or removed from the latency of each packet, reorders packets like a real network
+	PacketLoss int           // percent of the packets dropped
+	Seed       uint64        // seeds the jitter and loss rolls so a run can be repeated
+	Now        func() time.Time
+}
+
+// NewLoopback returns two connected in-process transports, what one sends the other receives after the simulated delay.
+// Sessions on both ends can run in the same goroutine or in different ones.
+func NewLoopback(config LoopbackConfig) (*LoopbackTransport, *LoopbackTransport) {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	aToB := &loopbackLink{config: config, rng: rand.New(rand.NewPCG(config.Seed, 1))}
+	bToA := &loopbackLink{config: config, rng: rand.New(rand.NewPCG(config.Seed, 2))}
+	return &LoopbackTransport{out: aToB, in: bToA}, &LoopbackTransport{out: bToA, in: aToB}
+}
+
+type LoopbackTransport struct {
+	out *loopbackLink
+	in  *loopbackLink
+}
+
+// loopbackLink holds the packets travelling in one direction
+type loopbackLink struct {
+	mu      sync.Mutex
+	config  LoopbackConfig
+	rng     *rand.Rand
+	packets []delayedPacket
+	closed  bool
+}
+
+type delayedPacket struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+func (t *LoopbackTransport) Send(packet []byte) error {
+	link := t.out
+	link.mu.Lock()
+	defer link.mu.Unlock()
+
+	if link.closed {
+		return ErrTransportClosed
+	}
+	if link.config.PacketLoss > 0 && link.rng.IntN(100) < link.config.PacketLoss {
+		return nil
+	}
+
+	delay := link.config.Latency
+	if link.config.Jitter > 0 {
+		delay += time.Duration(link.rng.Int64N(int64(2*link.config.Jitter+1))) - link.config.Jitter
+	}
+	link.packets = append(link.packets, delayedPacket{
+		data:      slices.Clone(packet),
+		deliverAt: link.config.Now().Add(max(delay, 0)),
+	})
+	return nil
+}
+
+// Receive returns the packet that arrived first among the ones whose delay is over
+func (t *LoopbackTransport) Receive() ([]byte, bool) {
+	link := t.in
+	link.mu.Lock()
+	defer link.mu.Unlock()
+
+	now := link.config.Now()
+	first := -1
+	for i, p := range link.packets {
+		if p.deliverAt.After(now) {
+			continue
+		}
+		if first < 0 || p.deliverAt.Before(link.packets[first].deliverAt) {
+			first = i
+		}
+	}
+	if first < 0 {
+		return nil, false
+	}
+	packet := link.packets[first].data
+	link.packets = slices.Delete(link.packets, first, first+1)
+	return packet, true
+}
+
+// Close stops both directions, packets already sent still arrive like they would over UDP
+func (t *LoopbackTransport) Close() error {
+	t.out.mu.Lock()
+	t.out.closed = true
+	t.out.mu.Unlock()
+
+	t.in.mu.Lock()
+	t.in.closed = true
+	t.in.packets = nil
+	t.in.mu.Unlock()
+	return nil
+}
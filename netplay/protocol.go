@@ -0,0 +1,94 @@
+package netplay
+
+import (
+	"encoding/binary"
+	"errors"
+	"fgengine/input"
+	"hash/fnv"
+)
+
+type messageKind byte
+
+const (
+	messageInput messageKind = iota + 1
+	messageQuit
+)
+
+const (
+	inputHeaderSize     = 1 + 4 + 8 + 4*5 + 8 + 1
+	maxInputsPerMessage = 64
+)
+
+var errBadMessage = errors.New("malformed netplay message")
+
+// inputMessage carries the sender's inputs that weren't acknowledged yet, every message repeats them so losses recover by themselves.
+// The version and data checksum of the sender come with every message, so the first one that arrives is enough to
+// refuse a peer that would simulate differently.
+type inputMessage struct {
+	Version       uint32 // see versionHash
+	Data          uint64 // see gameplay.GameState.DataChecksum
+	Frame         int    // next frame the sender will simulate
+	Advantage     int    // frames the sender thinks it is ahead of the receiver, see Session.syncWait
+	Ack           int    // last frame of the receiver's inputs the sender got, -1 for none
+	StartFrame    int    // frame of Inputs[0]
+	ChecksumFrame int    // frame of the sender's latest state checksum, -1 for none
+	Checksum      uint64
+	Inputs        []input.GameInput
+}
+
+// versionHash identifies an engine version in messages
+func versionHash(version string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(version))
+	return hash.Sum32()
+}
+
+func encodeInput(m inputMessage) []byte {
+	data := make([]byte, 0, inputHeaderSize+len(m.Inputs))
+	data = append(data, byte(messageInput))
+	data = binary.LittleEndian.AppendUint32(data, m.Version)
+	data = binary.LittleEndian.AppendUint64(data, m.Data)
+	data = binary.LittleEndian.AppendUint32(data, uint32(int32(m.Frame)))
+	data = binary.LittleEndian.AppendUint32(data, uint32(int32(m.Advantage)))
+	data = binary.LittleEndian.AppendUint32(data, uint32(int32(m.Ack)))
+	data = binary.LittleEndian.AppendUint32(data, uint32(int32(m.StartFrame)))
+	data = binary.LittleEndian.AppendUint32(data, uint32(int32(m.ChecksumFrame)))
+	data = binary.LittleEndian.AppendUint64(data, m.Checksum)
+	data = append(data, byte(len(m.Inputs)))
+	for _, in := range m.Inputs {
+		data = append(data, byte(in))
+	}
+	return data
+}
+
+func decodeInput(data []byte) (inputMessage, error) {
+	if len(data) < inputHeaderSize || messageKind(data[0]) != messageInput {
+		return inputMessage{}, errBadMessage
+	}
+	readInt := func(offset int) int {
+		return int(int32(binary.LittleEndian.Uint32(data[offset:])))
+	}
+	m := inputMessage{
+		Version:       binary.LittleEndian.Uint32(data[1:]),
+		Data:          binary.LittleEndian.Uint64(data[5:]),
+		Frame:         readInt(13),
+		Advantage:     readInt(17),
+		Ack:           readInt(21),
+		StartFrame:    readInt(25),
+		ChecksumFrame: readInt(29),
+		Checksum:      binary.LittleEndian.Uint64(data[33:]),
+	}
+	count := int(data[inputHeaderSize-1])
+	if len(data) != inputHeaderSize+count {
+		return inputMessage{}, errBadMessage
+	}
+	m.Inputs = make([]input.GameInput, count)
+	for i := range count {
+		m.Inputs[i] = input.GameInput(data[inputHeaderSize+i])
+	}
+	return m, nil
+}
+
+func encodeQuit() []byte {
+	return []byte{byte(messageQuit)}
+}
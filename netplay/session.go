@@ -0,0 +1,429 @@
+package netplay
+
+import (
+	"errors"
+	"fgengine/constants"
+	"fgengine/gameplay"
+	"fgengine/input"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultInputDelay        = 2
+	DefaultMaxPrediction     = 8
+	DefaultDisconnectTimeout = 3 * time.Second
+
+	inputRingSize = 128 // frames of inputs kept, far more than a session can be ahead of its peer
+	syncInterval  = 10  // minimum frames between two sync waits, so slowing down is spread out
+	syncThreshold = 1   // frames of lead over the remote before waiting
+
+	checksumInterval = 60 // frames between two compared state checksums
+	checksumRingSize = 8  // checksums kept by each side, the peers are never that far apart
+)
+
+var (
+	ErrDisconnected = errors.New("netplay peer disconnected")
+	ErrIncompatible = errors.New("netplay peer is incompatible")
+	ErrDesync       = errors.New("netplay peers desynced")
+)
+
+// Game is the simulation a session rolls back, *gameplay.GameState implements it
+type Game interface {
+	Update(inputs [2]input.GameInput)
+	Save() gameplay.Snapshot
+	Load(s *gameplay.Snapshot)
+	DataChecksum() (uint64, error)
+}
+
+type Config struct {
+	LocalPlayer       int           // index of the local player, 0 or 1
+	InputDelay        int           // frames local inputs are delayed by, a delay close to the latency avoids most rollbacks
+	MaxPrediction     int           // frames the session can simulate past the last remote input before it stalls
+	DisconnectTimeout time.Duration // silence after which the peer is considered gone, counted from the start until its first packet
+	Now               func() time.Time
+}
+
+func DefaultConfig(localPlayer int) Config {
+	return Config{
+		LocalPlayer:       localPlayer,
+		InputDelay:        DefaultInputDelay,
+		MaxPrediction:     DefaultMaxPrediction,
+		DisconnectTimeout: DefaultDisconnectTimeout,
+		Now:               time.Now,
+	}
+}
+
+// Stats describe the session for debugging
+type Stats struct {
+	Frame            int
+	RemoteFrame      int
+	Rollbacks        int
+	RolledBackFrames int
+	Stalls           int // frames skipped waiting for the remote, by prediction limit or time sync
+}
+
+// Session runs a two player match over a transport GGPO style: the remote inputs that didn't arrive yet are predicted,
+// and when they arrive different from the prediction the game is loaded back to that frame and simulated again.
+type Session struct {
+	config    Config
+	transport Transport
+
+	frame        int // next frame to simulate
+	local        inputRing
+	remote       inputRing
+	predicted    inputRing // remote inputs used by the simulation past remote.last
+	mispredicted int       // first frame simulated with a wrong prediction, -1 when none
+	snapshots    []savedFrame
+
+	remoteFrame     int // latest frame the remote reported
+	remoteAdvantage int
+	remoteAck       int // last local frame the remote received
+	lastSync        int // frame of the last sync wait
+	reported        int // next frame ConfirmedInputs returns
+
+	version   uint32
+	data      uint64 // checksum of the game data, set by the first AdvanceFrame
+	dataKnown bool
+
+	// every checksumInterval frames both sides hash the state saved before the frame once it is confirmed
+	checksums       checksumRing
+	remoteChecksums checksumRing
+	nextChecksum    int
+	lastChecksum    int // frame of the latest local checksum, -1 for none
+
+	connected    bool      // a packet from the peer arrived
+	failure      error     // ends the session, like a disconnect or a desync
+	lastReceived time.Time // or the creation of the session before the first packet
+
+	stats Stats
+}
+
+type savedFrame struct {
+	frame    int
+	snapshot gameplay.Snapshot
+}
+
+// inputRing keeps the inputs of the last inputRingSize frames
+type inputRing struct {
+	inputs [inputRingSize]input.GameInput
+	last   int // highest frame set, -1 before the first
+}
+
+func (r *inputRing) set(frame int, in input.GameInput) {
+	r.inputs[frame%inputRingSize] = in
+	r.last = max(r.last, frame)
+}
+
+func (r *inputRing) get(frame int) input.GameInput {
+	return r.inputs[frame%inputRingSize]
+}
+
+type frameChecksum struct {
+	frame int
+	sum   uint64
+}
+
+// checksumRing keeps the checksums of the last checksumRingSize checked frames
+type checksumRing [checksumRingSize]frameChecksum
+
+func (r *checksumRing) set(frame int, sum uint64) {
+	r[frame/checksumInterval%checksumRingSize] = frameChecksum{frame: frame, sum: sum}
+}
+
+func (r *checksumRing) get(frame int) (uint64, bool) {
+	saved := r[frame/checksumInterval%checksumRingSize]
+	return saved.sum, saved.frame == frame
+}
+
+func NewSession(transport Transport, config Config) *Session {
+	if config.MaxPrediction <= 0 {
+		config.MaxPrediction = DefaultMaxPrediction
+	}
+	if config.DisconnectTimeout <= 0 {
+		config.DisconnectTimeout = DefaultDisconnectTimeout
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	config.InputDelay = max(config.InputDelay, 0)
+
+	s := &Session{
+		config:       config,
+		transport:    transport,
+		local:        inputRing{last: -1},
+		remote:       inputRing{last: -1},
+		predicted:    inputRing{last: -1},
+		mispredicted: -1,
+		snapshots:    make([]savedFrame, config.MaxPrediction+2),
+		remoteAck:    -1,
+		version:      versionHash(constants.EngineVersion),
+		nextChecksum: checksumInterval,
+		lastChecksum: -1,
+		lastReceived: config.Now(),
+	}
+	// the delayed frames at the start have no local input
+	for frame := range config.InputDelay {
+		s.local.set(frame, input.NoInput)
+	}
+	return s
+}
+
+// AdvanceFrame syncs with the remote player and returns the inputs of the next frame. When advance is true the caller must
+// run exactly one game update with them, when it's false the session is waiting for the remote and the frame is skipped.
+// Frames simulated with a wrong prediction are rolled back and simulated again before returning.
+// Once it returns an error, like ErrDisconnected, ErrIncompatible or ErrDesync, the session is over.
+func (s *Session) AdvanceFrame(game Game, localInput input.GameInput) (inputs [2]input.GameInput, advance bool, err error) {
+	if err := s.sync(game); err != nil {
+		return inputs, false, err
+	}
+
+	if s.frame-s.remote.last > s.config.MaxPrediction || s.syncWait() {
+		s.stats.Stalls++
+		s.sendInputs()
+		return inputs, false, nil
+	}
+
+	s.local.set(s.frame+s.config.InputDelay, localInput)
+	s.saveFrame(game, s.frame)
+	inputs = s.inputsFor(s.frame)
+	s.frame++
+	s.sendInputs()
+	return inputs, true, nil
+}
+
+// Close tells the remote player the session is over and closes the transport
+func (s *Session) Close() error {
+	_ = s.transport.Send(encodeQuit())
+	return s.transport.Close()
+}
+
+// Confirmed returns true when every simulated frame used the real remote inputs, results like a match ending are final then
+func (s *Session) Confirmed() bool {
+	return s.remote.last >= s.frame-1 && s.mispredicted < 0
+}
+
+// ConfirmedInputs returns the inputs of the frames confirmed since the last call, in order. Unlike the inputs returned by
+// AdvanceFrame they never change, so they are the ones to record. It must be called every frame, older inputs are dropped.
+func (s *Session) ConfirmedInputs() [][2]input.GameInput {
+	end := min(s.frame-1, s.remote.last)
+	if s.mispredicted >= 0 {
+		end = min(end, s.mispredicted-1)
+	}
+	var confirmed [][2]input.GameInput
+	for ; s.reported <= end; s.reported++ {
+		confirmed = append(confirmed, s.inputsFor(s.reported))
+	}
+	return confirmed
+}
+
+func (s *Session) Stats() Stats {
+	stats := s.stats
+	stats.Frame = s.frame
+	stats.RemoteFrame = s.remoteFrame
+	return stats
+}
+
+// sync reads the packets of the remote, rolls back the mispredicted frames and compares the checksums of the frames
+// confirmed since the last call
+func (s *Session) sync(game Game) error {
+	if !s.dataKnown {
+		data, err := game.DataChecksum()
+		if err != nil {
+			return err
+		}
+		s.data, s.dataKnown = data, true
+	}
+	s.poll()
+	if s.failure != nil {
+		return s.failure
+	}
+	if err := s.rollback(game); err != nil {
+		return err
+	}
+	if err := s.checkState(); err != nil {
+		return err
+	}
+	return s.failure
+}
+
+// checkState hashes the saved states that only depend on confirmed inputs, every checksumInterval frames. The state
+// saved before a frame is final once every frame before it is confirmed and the rollbacks are done.
+func (s *Session) checkState() error {
+	for s.nextChecksum < s.frame && s.nextChecksum <= s.remote.last+1 {
+		frame := s.nextChecksum
+		s.nextChecksum += checksumInterval
+		saved := &s.snapshots[frame%len(s.snapshots)]
+		if saved.frame != frame {
+			continue
+		}
+		sum, err := saved.snapshot.Checksum()
+		if err != nil {
+			return err
+		}
+		s.checksums.set(frame, sum)
+		s.lastChecksum = frame
+		s.compareChecksums(frame)
+	}
+	return nil
+}
+
+// compareChecksums fails the session when both sides have a checksum of the frame and they differ
+func (s *Session) compareChecksums(frame int) {
+	local, ok := s.checksums.get(frame)
+	if !ok {
+		return
+	}
+	remote, ok := s.remoteChecksums.get(frame)
+	if ok && local != remote {
+		s.failure = fmt.Errorf("%w at frame %d: local state %016x, remote %016x", ErrDesync, frame, local, remote)
+	}
+}
+
+// poll reads every packet that arrived and checks for a timeout
+func (s *Session) poll() {
+	for {
+		packet, ok := s.transport.Receive()
+		if !ok {
+			break
+		}
+		s.handlePacket(packet)
+	}
+	if s.failure == nil && s.config.Now().Sub(s.lastReceived) > s.config.DisconnectTimeout {
+		s.failure = ErrDisconnected
+	}
+}
+
+func (s *Session) handlePacket(packet []byte) {
+	if len(packet) == 0 {
+		return
+	}
+
+	switch messageKind(packet[0]) {
+	case messageQuit:
+		s.failure = ErrDisconnected
+	case messageInput:
+		m, err := decodeInput(packet)
+		if err != nil {
+			return
+		}
+		switch {
+		case m.Version != s.version:
+			s.failure = fmt.Errorf("%w: it runs another engine version than %s", ErrIncompatible, constants.EngineVersion)
+			return
+		case m.Data != s.data:
+			s.failure = fmt.Errorf("%w: its characters or rules are different", ErrIncompatible)
+			return
+		}
+		s.connected = true
+		s.lastReceived = s.config.Now()
+
+		// packets can come out of order, older ones don't override the remote frame
+		if m.Frame > s.remoteFrame {
+			s.remoteFrame = m.Frame
+			s.remoteAdvantage = m.Advantage
+		}
+		s.remoteAck = max(s.remoteAck, m.Ack)
+		if m.ChecksumFrame >= 0 {
+			s.remoteChecksums.set(m.ChecksumFrame, m.Checksum)
+			s.compareChecksums(m.ChecksumFrame)
+		}
+
+		for i, in := range m.Inputs {
+			frame := m.StartFrame + i
+			if frame != s.remote.last+1 {
+				continue
+			}
+			s.remote.set(frame, in)
+			if frame < s.frame && s.predicted.get(frame) != in && (s.mispredicted < 0 || frame < s.mispredicted) {
+				s.mispredicted = frame
+			}
+		}
+	}
+}
+
+// rollback loads the first mispredicted frame and simulates every frame since then with the inputs known now
+func (s *Session) rollback(game Game) error {
+	if s.mispredicted < 0 {
+		return nil
+	}
+	start := s.mispredicted
+	s.mispredicted = -1
+
+	saved := &s.snapshots[start%len(s.snapshots)]
+	if saved.frame != start {
+		return fmt.Errorf("no snapshot of frame %d to roll back to", start)
+	}
+	game.Load(&saved.snapshot)
+
+	for frame := start; frame < s.frame; frame++ {
+		if frame > start {
+			s.saveFrame(game, frame)
+		}
+		game.Update(s.inputsFor(frame))
+	}
+	s.stats.Rollbacks++
+	s.stats.RolledBackFrames += s.frame - start
+	return nil
+}
+
+// saveFrame keeps the state before the frame is simulated
+func (s *Session) saveFrame(game Game, frame int) {
+	s.snapshots[frame%len(s.snapshots)] = savedFrame{frame: frame, snapshot: game.Save()}
+}
+
+// inputsFor returns the inputs of a frame, the remote keeps holding its last known input until newer ones arrive
+func (s *Session) inputsFor(frame int) [2]input.GameInput {
+	remote := input.NoInput
+	switch {
+	case frame <= s.remote.last:
+		remote = s.remote.get(frame)
+	case s.remote.last >= 0:
+		remote = s.remote.get(s.remote.last)
+	}
+	if frame > s.remote.last {
+		s.predicted.set(frame, remote)
+	}
+
+	var inputs [2]input.GameInput
+	inputs[s.config.LocalPlayer] = s.local.get(frame)
+	inputs[1-s.config.LocalPlayer] = remote
+	return inputs
+}
+
+// sendInputs sends the local inputs the remote didn't acknowledge yet, send errors are treated like lost packets
+func (s *Session) sendInputs() {
+	start := s.remoteAck + 1
+	end := min(s.local.last, start+maxInputsPerMessage-1)
+
+	m := inputMessage{
+		Version:       s.version,
+		Data:          s.data,
+		Frame:         s.frame,
+		Advantage:     s.frame - s.remoteFrame,
+		Ack:           s.remote.last,
+		StartFrame:    start,
+		ChecksumFrame: s.lastChecksum,
+	}
+	if s.lastChecksum >= 0 {
+		m.Checksum, _ = s.checksums.get(s.lastChecksum)
+	}
+	for frame := start; frame <= end; frame++ {
+		m.Inputs = append(m.Inputs, s.local.get(frame))
+	}
+	_ = s.transport.Send(encodeInput(m))
+}
+
+// syncWait returns true when this side is ahead of the remote and should skip a frame. Each side sees the other
+// behind by the latency, half the difference of both advantages cancels it out.
+func (s *Session) syncWait() bool {
+	if !s.connected || s.frame-s.lastSync < syncInterval {
+		return false
+	}
+	localAdvantage := s.frame - s.remoteFrame
+	if (localAdvantage-s.remoteAdvantage)/2 < syncThreshold {
+		return false
+	}
+	s.lastSync = s.frame
+	return true
+}
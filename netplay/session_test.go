@@ -0,0 +1,314 @@
+package netplay
+
+import (
+	"errors"
+	"fgengine/character"
+	"fgengine/gameplay"
+	"fgengine/input"
+	"os"
+	"testing"
+	"time"
+)
+
+const frameTime = time.Second / 60
+
+// clock is the time of every session and transport of a test, it only moves when the test advances it
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) advance() {
+	c.now = c.now.Add(frameTime)
+}
+
+func newTestGame(t *testing.T) *gameplay.GameState {
+	t.Helper()
+	var characters [2]*character.Character
+	for i := range characters {
+		var err error
+		characters[i], err = character.LoadCharacterFile("../gameplay/testdata/characters/sparring.yaml", i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &gameplay.GameState{Characters: characters, Rules: gameplay.DefaultRules()}
+}
+
+func loadScript(t *testing.T, path string) [][2]input.GameInput {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	frames, err := input.ReadScript(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frames
+}
+
+// peer is one end of a loopback match
+type peer struct {
+	session *Session
+	game    *gameplay.GameState
+}
+
+func newPeers(t *testing.T, c *clock, link LoopbackConfig) [2]*peer {
+	t.Helper()
+	link.Now = c.Now
+	a, b := NewLoopback(link)
+
+	var peers [2]*peer
+	for i, transport := range []Transport{a, b} {
+		config := DefaultConfig(i)
+		config.Now = c.Now
+		peers[i] = &peer{session: NewSession(transport, config), game: newTestGame(t)}
+	}
+	return peers
+}
+
+// step runs a frame of the session with the local input of the script
+func (p *peer) step(script [][2]input.GameInput) error {
+	local := input.NoInput
+	player := p.session.config.LocalPlayer
+	if frame := p.session.frame + p.session.config.InputDelay; frame < len(script) {
+		local = script[frame][player]
+	}
+	inputs, advance, err := p.session.AdvanceFrame(p.game, local)
+	if advance {
+		p.game.Update(inputs)
+	}
+	return err
+}
+
+// wait keeps a session that reached the end of the script talking to its peer without simulating more frames
+func (p *peer) wait() error {
+	if err := p.session.sync(p.game); err != nil {
+		return err
+	}
+	p.session.sendInputs()
+	return nil
+}
+
+func TestLoopbackSessionsStayInSync(t *testing.T) {
+	script := loadScript(t, "../gameplay/testdata/synctest/pressure.inputs")
+	conditions := []struct {
+		name string
+		link LoopbackConfig
+	}{
+		{"lan", LoopbackConfig{Latency: 5 * time.Millisecond, Seed: 1}},
+		{"jitter", LoopbackConfig{Latency: 50 * time.Millisecond, Jitter: 30 * time.Millisecond, Seed: 2}},
+		{"loss", LoopbackConfig{Latency: 80 * time.Millisecond, Jitter: 20 * time.Millisecond, PacketLoss: 20, Seed: 3}},
+	}
+
+	for _, condition := range conditions {
+		t.Run(condition.name, func(t *testing.T) {
+			c := &clock{now: time.Unix(0, 0)}
+			peers := newPeers(t, c, condition.link)
+
+			for tick := 0; ; tick++ {
+				if tick > 10*len(script) {
+					t.Fatalf("sessions didn't finish, frames %d and %d", peers[0].session.frame, peers[1].session.frame)
+				}
+				c.advance()
+				done := true
+				for _, p := range peers {
+					var err error
+					if p.session.frame < len(script) {
+						err = p.step(script)
+						done = false
+					} else {
+						err = p.wait()
+						done = done && p.session.Confirmed()
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				if done {
+					break
+				}
+			}
+
+			var checksums [2]uint64
+			for i, p := range peers {
+				snapshot := p.game.Save()
+				sum, err := snapshot.Checksum()
+				if err != nil {
+					t.Fatal(err)
+				}
+				checksums[i] = sum
+			}
+			if checksums[0] != checksums[1] {
+				t.Fatalf("checksums differ after %d frames: %016x and %016x", len(script), checksums[0], checksums[1])
+			}
+
+			// a local game without a network is the reference
+			local := newTestGame(t)
+			for _, inputs := range script {
+				local.Update(inputs)
+			}
+			snapshot := local.Save()
+			if sum, err := snapshot.Checksum(); err != nil || sum != checksums[0] {
+				t.Fatalf("sessions ended at %016x, the local game at %016x (%v)", checksums[0], sum, err)
+			}
+
+			if condition.link.Latency > frameTime {
+				rollbacks := peers[0].session.Stats().Rollbacks + peers[1].session.Stats().Rollbacks
+				if rollbacks == 0 {
+					t.Error("no rollback happened")
+				}
+			}
+		})
+	}
+}
+
+func TestSessionDisconnects(t *testing.T) {
+	script := loadScript(t, "../gameplay/testdata/synctest/neutral.inputs")
+	link := LoopbackConfig{Latency: 30 * time.Millisecond, Seed: 4}
+
+	t.Run("quit", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		for range 60 {
+			c.advance()
+			for _, p := range peers {
+				if err := p.step(script); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		if err := peers[0].session.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// the quit arrives after the latency, far before the timeout
+		for range 10 {
+			c.advance()
+			if err := peers[1].step(script); errors.Is(err, ErrDisconnected) {
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Fatal("the quit didn't disconnect the peer")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		for range 60 {
+			c.advance()
+			for _, p := range peers {
+				if err := p.step(script); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		// the first peer goes silent without quitting
+		start := c.now
+		for {
+			c.advance()
+			err := peers[1].step(script)
+			if errors.Is(err, ErrDisconnected) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.now.Sub(start) > 2*DefaultDisconnectTimeout {
+				t.Fatal("no timeout")
+			}
+		}
+		if silence := c.now.Sub(start); silence < DefaultDisconnectTimeout {
+			t.Fatalf("disconnected after %v of silence, before the timeout", silence)
+		}
+	})
+
+	t.Run("never connected", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		for {
+			c.advance()
+			err := peers[0].step(script)
+			if errors.Is(err, ErrDisconnected) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.now.Sub(time.Unix(0, 0)) > 2*DefaultDisconnectTimeout {
+				t.Fatal("no timeout while the peer never connected")
+			}
+		}
+		if frame := peers[0].session.frame; frame > DefaultMaxPrediction {
+			t.Fatalf("simulated %d frames without the peer", frame)
+		}
+	})
+}
+
+func TestSessionChecks(t *testing.T) {
+	script := loadScript(t, "../gameplay/testdata/synctest/neutral.inputs")
+	link := LoopbackConfig{Latency: 30 * time.Millisecond, Seed: 5}
+
+	// run steps both peers until one of them fails and returns the error of each
+	run := func(c *clock, peers [2]*peer, frames int) [2]error {
+		var errs [2]error
+		for range frames {
+			c.advance()
+			for i, p := range peers {
+				if errs[i] == nil {
+					errs[i] = p.step(script)
+				}
+			}
+			if errs[0] != nil || errs[1] != nil {
+				break
+			}
+		}
+		return errs
+	}
+
+	t.Run("other rules", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		peers[1].game.Rules.RoundFrames++
+		for i, err := range run(c, peers, 30) {
+			if !errors.Is(err, ErrIncompatible) {
+				t.Errorf("peer %d: got %v, want %v", i+1, err, ErrIncompatible)
+			}
+		}
+	})
+
+	t.Run("other engine version", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		peers[0].session.version = versionHash("0.0.0")
+		for i, err := range run(c, peers, 30) {
+			if !errors.Is(err, ErrIncompatible) {
+				t.Errorf("peer %d: got %v, want %v", i+1, err, ErrIncompatible)
+			}
+		}
+	})
+
+	t.Run("desync", func(t *testing.T) {
+		c := &clock{now: time.Unix(0, 0)}
+		peers := newPeers(t, c, link)
+		if errs := run(c, peers, 3*checksumInterval); errs != [2]error{} {
+			t.Fatalf("peers in sync failed: %v", errs)
+		}
+
+		// the first peer simulates something the second one doesn't
+		peers[0].game.Characters[1].StateMachine.HP--
+		for i, err := range run(c, peers, 2*checksumInterval) {
+			if !errors.Is(err, ErrDesync) {
+				t.Errorf("peer %d: got %v, want %v", i+1, err, ErrDesync)
+			}
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	maxPacketSize   = 1500
+	receiveQueueLen = 256 // packets waiting to be read, newer ones are dropped when it's full
+)
+
+// Transport carries the session packets to the other player, delivery isn't guaranteed and packets can come out of order.
+type Transport interface {
+	Send(packet []byte) error
+	// Receive returns the next packet that arrived, false when none is waiting. It never blocks.
+	Receive() ([]byte, bool)
+	Close() error
+}
+
+// UDPTransport sends packets to a single remote address, packets from any other address are ignored.
+type UDPTransport struct {
+	conn    *net.UDPConn
+	remote  *net.UDPAddr
+	packets chan []byte
+}
+
+// DialUDP listens on localAddr and talks to remoteAddr, like ":7000" and "192.168.0.10:7000"
+func DialUDP(localAddr, remoteAddr string) (*UDPTransport, error) {
+	local, err := net.ResolveUDPAddr("udp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local address: %w", err)
+	}
+	remote, err := net.ResolveUDPAddr("udp", remoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve remote address: %w", err)
+	}
+	conn, err := net.ListenUDP("udp", local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", localAddr, err)
+	}
+
+	t := &UDPTransport{
+		conn:    conn,
+		remote:  remote,
+		packets: make(chan []byte, receiveQueueLen),
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+// readLoop moves the packets from the socket to the queue until the connection is closed
+func (t *UDPTransport) readLoop() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, from, err := t.conn.ReadFromUDP(buf)
+		if errors.Is(err, net.ErrClosed) {
+			close(t.packets)
+			return
+		}
+		if err != nil || !from.IP.Equal(t.remote.IP) || from.Port != t.remote.Port {
+			continue
+		}
+
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+		select {
+		case t.packets <- packet:
+		default:
+		}
+	}
+}
+
+func (t *UDPTransport) Send(packet []byte) error {
+	_, err := t.conn.WriteToUDP(packet, t.remote)
+	return err
+}
+
+func (t *UDPTransport) Receive() ([]byte, bool) {
+	select {
+	case packet, ok := <-t.packets:
+		return packet, ok
+	default:
+		return nil, false
+	}
+}
+
+func (t *UDPTransport) Close() error {
+	return t.conn.Close()
+}
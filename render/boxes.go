@@ -1,8 +1,9 @@
-package character
+package render
 
 import (
 	"fgengine/animation"
-
+	"fgengine/character"
+	"fgengine/entity"
 	"fgengine/graphics"
 	"fgengine/types"
 	"image/color"
@@ -18,6 +19,8 @@ var (
 		types.Collision: {R: 80, G: 80, B: 80, A: 32},
 		types.Hit:       {R: 100, G: 40, B: 40, A: 32},
 		types.Hurt:      {R: 40, G: 100, B: 40, A: 32},
+		types.Throw:     {R: 100, G: 40, B: 100, A: 32},
+		types.Throwable: {R: 40, G: 40, B: 100, A: 32},
 	}
 )
 
@@ -29,7 +32,7 @@ func initWhitePixel() {
 	})
 }
 
-func (c *Character) newBoxOpts(box types.Rect, boxType types.BoxType, camera *graphics.Camera) *ebiten.DrawImageOptions {
+func characterBoxOpts(c *character.Character, box types.Rect, boxType types.BoxType, camera *graphics.Camera) *ebiten.DrawImageOptions {
 	boxImgOptions := &ebiten.DrawImageOptions{}
 
 	boxImgOptions.GeoM.Scale(box.W, box.H)
@@ -62,7 +65,8 @@ func (c *Character) newBoxOpts(box types.Rect, boxType types.BoxType, camera *gr
 	return boxImgOptions
 }
 
-func (c *Character) DrawBoxes(screen *ebiten.Image, camera *graphics.Camera) {
+// DrawCharacterBoxes draws the boxes of the current frame of a character
+func DrawCharacterBoxes(screen *ebiten.Image, camera *graphics.Camera, c *character.Character) {
 	framedata := c.StateMachine.AnimPlayer.ActiveFrameData()
 	if framedata == nil || len(framedata.Boxes) == 0 {
 		return
@@ -70,7 +74,38 @@ func (c *Character) DrawBoxes(screen *ebiten.Image, camera *graphics.Camera) {
 	initWhitePixel()
 	for boxType, boxes := range framedata.Boxes {
 		for _, box := range boxes {
-			opts := c.newBoxOpts(box, boxType, camera)
+			opts := characterBoxOpts(c, box, boxType, camera)
+			screen.DrawImage(whitePixel, opts)
+		}
+	}
+}
+
+// DrawEntityBoxes draws the boxes of the current frame of an entity
+func DrawEntityBoxes(screen *ebiten.Image, camera *graphics.Camera, e *entity.Entity) {
+	if e.Despawned || camera == nil {
+		return
+	}
+	frameData := e.AnimPlayer.ActiveFrameData()
+	if frameData == nil || len(frameData.Boxes) == 0 {
+		return
+	}
+	initWhitePixel()
+
+	anchor := entityAnchor(e)
+	position := e.Position.Float()
+	for boxType, boxes := range frameData.Boxes {
+		for _, box := range boxes {
+			boxWorldPos := types.Vector2{X: position.X + box.X - anchor.X, Y: position.Y + box.Y - anchor.Y}
+			if e.IsFacingLeft == animation.Left {
+				boxWorldPos.X = position.X - box.X - box.W + anchor.X
+			}
+
+			opts := &ebiten.DrawImageOptions{}
+			opts.GeoM.Scale(box.W, box.H)
+			graphics.CameraTransform(opts, camera, types.Vector2{X: 1, Y: 1}, camera.WorldToScreen(boxWorldPos))
+			if col, exists := boxColors[boxType]; exists {
+				opts.ColorScale.ScaleWithColor(col)
+			}
 			screen.DrawImage(whitePixel, opts)
 		}
 	}
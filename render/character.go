@@ -1,7 +1,8 @@
-package character
+package render
 
 import (
 	"fgengine/animation"
+	"fgengine/character"
 	"fgengine/graphics"
 
 	"fgengine/types"
@@ -10,7 +11,8 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
-func (c *Character) Draw(screen *ebiten.Image, camera *graphics.Camera) {
+// DrawCharacter draws the current sprite of a character with the name of its animation over it
+func DrawCharacter(screen *ebiten.Image, camera *graphics.Camera, c *character.Character) {
 	var img *ebiten.Image
 	sprite := c.Sprite()
 	if sprite == nil {
@@ -36,7 +38,7 @@ func (c *Character) Draw(screen *ebiten.Image, camera *graphics.Camera) {
 
 	if state != nil {
 		if camera != nil {
-			screenPos = camera.WorldToScreen(state.Position)
+			screenPos = camera.WorldToScreen(state.Position.Float())
 		}
 
 		// Aplicar deslocamento para compensar o anchor point
@@ -50,6 +52,11 @@ func (c *Character) Draw(screen *ebiten.Image, camera *graphics.Camera) {
 			op.GeoM.Translate(2*anchorOffset.X, 0)
 		}
 
+		// blink while the armor flash lasts
+		if state.FlashFrames > 0 && (state.FlashFrames/2)%2 == 0 {
+			op.ColorScale.Scale(1.8, 1.8, 1.8, 1)
+		}
+
 		graphics.CameraTransform(op, camera, types.Vector2{X: 1, Y: 1}, screenPos)
 		screen.DrawImage(img, op)
 
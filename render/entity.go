@@ -0,0 +1,42 @@
+package render
+
+import (
+	"fgengine/animation"
+	"fgengine/entity"
+	"fgengine/graphics"
+	"fgengine/types"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// entityAnchor returns the anchor of the current sprite, entities without a sprite are drawn from their position
+func entityAnchor(e *entity.Entity) types.Vector2 {
+	if sprite := e.Sprite(); sprite != nil {
+		return sprite.Anchor
+	}
+	return types.Vector2{}
+}
+
+func DrawEntity(screen *ebiten.Image, camera *graphics.Camera, e *entity.Entity) {
+	if e.Despawned || camera == nil {
+		return
+	}
+	sprite := e.Sprite()
+	if sprite == nil {
+		return
+	}
+	img := graphics.LoadImage(sprite.ImagePath)
+
+	anchor := entityAnchor(e)
+	screenPos := camera.WorldToScreen(e.Position.Float())
+	screenPos.X -= anchor.X
+	screenPos.Y -= anchor.Y
+
+	op := &ebiten.DrawImageOptions{}
+	if e.IsFacingLeft == animation.Left {
+		op.GeoM.Scale(-1, 1)
+		op.GeoM.Translate(2*anchor.X, 0)
+	}
+	graphics.CameraTransform(op, camera, types.Vector2{X: 1, Y: 1}, screenPos)
+	screen.DrawImage(img, op)
+}
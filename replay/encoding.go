@@ -0,0 +1,105 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fgengine/input"
+	"fmt"
+)
+
+// FormatVersion changes with the file layout, older files are rejected instead of read wrong
+const FormatVersion = 1
+
+// maxFrames is three hours at 60 fps, a longer replay is a broken file
+const maxFrames = 3 * 60 * 60 * 60
+
+var magic = [4]byte{'F', 'G', 'R', 'P'}
+
+var ErrNotReplay = errors.New("not a replay file")
+
+// MarshalBinary encodes the replay as:
+//
+//	magic "FGRP", format version (uint16)
+//	header length (uvarint), gob encoded Header
+//	frame count (uvarint), then runs of equal frames: run length (uvarint), player one input, player two input
+//
+// Inputs stay the same for many frames in a row, the runs keep a whole match in a few kilobytes.
+func (r *Replay) MarshalBinary() ([]byte, error) {
+	var header bytes.Buffer
+	if err := gob.NewEncoder(&header).Encode(r.Header); err != nil {
+		return nil, fmt.Errorf("failed to encode replay header: %w", err)
+	}
+
+	data := append([]byte{}, magic[:]...)
+	data = binary.LittleEndian.AppendUint16(data, FormatVersion)
+	data = binary.AppendUvarint(data, uint64(header.Len()))
+	data = append(data, header.Bytes()...)
+
+	data = binary.AppendUvarint(data, uint64(len(r.Inputs)))
+	for start := 0; start < len(r.Inputs); {
+		end := start + 1
+		for end < len(r.Inputs) && r.Inputs[end] == r.Inputs[start] {
+			end++
+		}
+		data = binary.AppendUvarint(data, uint64(end-start))
+		data = append(data, byte(r.Inputs[start][0]), byte(r.Inputs[start][1]))
+		start = end
+	}
+	return data, nil
+}
+
+func (r *Replay) UnmarshalBinary(data []byte) error {
+	if len(data) < len(magic)+2 || !bytes.Equal(data[:len(magic)], magic[:]) {
+		return ErrNotReplay
+	}
+	data = data[len(magic):]
+	if version := binary.LittleEndian.Uint16(data); version != FormatVersion {
+		return fmt.Errorf("%w: format version %d, expected %d", ErrIncompatible, version, FormatVersion)
+	}
+	data = data[2:]
+
+	headerLen, data, err := readUvarint(data)
+	if err != nil || headerLen > uint64(len(data)) {
+		return fmt.Errorf("%w: truncated header", ErrNotReplay)
+	}
+	var header Header
+	if err := gob.NewDecoder(bytes.NewReader(data[:headerLen])).Decode(&header); err != nil {
+		return fmt.Errorf("failed to decode replay header: %w", err)
+	}
+	data = data[headerLen:]
+
+	frames, data, err := readUvarint(data)
+	if err != nil || frames > maxFrames {
+		return fmt.Errorf("%w: bad frame count", ErrNotReplay)
+	}
+	inputs := make([][2]input.GameInput, 0, frames)
+	for uint64(len(inputs)) < frames {
+		var run uint64
+		run, data, err = readUvarint(data)
+		if err != nil || len(data) < 2 || run == 0 || run > frames-uint64(len(inputs)) {
+			return fmt.Errorf("%w: broken input run", ErrNotReplay)
+		}
+		frame := [2]input.GameInput{input.GameInput(data[0]), input.GameInput(data[1])}
+		data = data[2:]
+		for range run {
+			inputs = append(inputs, frame)
+		}
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: data after the inputs", ErrNotReplay)
+	}
+
+	r.Header = header
+	r.Inputs = inputs
+	return nil
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	value, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, data, ErrNotReplay
+	}
+	return value, data[n:], nil
+}
@@ -0,0 +1,109 @@
+package replay
+
+import (
+	"errors"
+	"fgengine/character"
+	"fgengine/constants"
+	"fgengine/gameplay"
+	"fgengine/input"
+	"fmt"
+	"os"
+)
+
+const (
+	DefaultDir    = "./replays" // where finished matches are saved
+	FileExtension = ".fgr"
+)
+
+var ErrIncompatible = errors.New("replay is incompatible with this game")
+
+// Replay is enough to play a match again: the setup it started from and the inputs of every frame.
+// The simulation is deterministic, so the same setup and inputs always give the same match.
+type Replay struct {
+	Header Header
+	Inputs [][2]input.GameInput
+}
+
+type Header struct {
+	EngineVersion string
+	Characters    [2]CharacterInfo
+	Stage         string // only changes the background, the simulation doesn't use it
+	Seed          uint64 // RNG state on the first frame
+	Rules         gameplay.Rules
+}
+
+// CharacterInfo finds the character file again and tells if it changed since the recording
+type CharacterInfo struct {
+	FilePath string
+	Checksum uint64
+}
+
+// New starts a replay of a game that didn't run any frame yet
+func New(game *gameplay.GameState, stage string) *Replay {
+	r := &Replay{
+		Header: Header{
+			EngineVersion: constants.EngineVersion,
+			Stage:         stage,
+			Seed:          game.RNG.State,
+			Rules:         game.Rules,
+		},
+	}
+	for i, char := range game.Characters {
+		r.Header.Characters[i] = CharacterInfo{FilePath: char.FilePath, Checksum: char.DataChecksum}
+	}
+	return r
+}
+
+// Record adds the inputs of the next frame
+func (r *Replay) Record(inputs [2]input.GameInput) {
+	r.Inputs = append(r.Inputs, inputs)
+}
+
+// NewGame loads the characters and returns the game state the replay starts from.
+// Characters whose file changed since the recording are rejected, the match would play differently.
+func (r *Replay) NewGame() (*gameplay.GameState, error) {
+	if r.Header.EngineVersion != constants.EngineVersion {
+		return nil, fmt.Errorf("%w: recorded with engine %s, this is %s", ErrIncompatible, r.Header.EngineVersion, constants.EngineVersion)
+	}
+
+	var characters [2]*character.Character
+	for i, info := range r.Header.Characters {
+		char, err := character.LoadCharacterFile(info.FilePath, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if char.DataChecksum != info.Checksum {
+			return nil, fmt.Errorf("%w: %s changed since the recording", ErrIncompatible, info.FilePath)
+		}
+		characters[i] = char
+	}
+
+	return &gameplay.GameState{
+		Characters: characters,
+		Rules:      r.Header.Rules,
+		RNG:        gameplay.RNG{State: r.Header.Seed},
+	}, nil
+}
+
+func Save(path string, r *Replay) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	return nil
+}
+
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+	r := &Replay{}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,163 @@
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"fgengine/character"
+	"fgengine/gameplay"
+	"fgengine/input"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sparringPath = "../gameplay/testdata/characters/sparring.yaml"
+
+func newTestGame(t *testing.T) *gameplay.GameState {
+	t.Helper()
+	var characters [2]*character.Character
+	for i := range characters {
+		var err error
+		if characters[i], err = character.LoadCharacterFile(sparringPath, i+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &gameplay.GameState{Characters: characters, Rules: gameplay.DefaultRules(), RNG: gameplay.RNG{State: 42}}
+}
+
+// testReplay has long runs of held inputs and short runs of single frames
+func testReplay() *Replay {
+	r := &Replay{
+		Header: Header{
+			EngineVersion: "test",
+			Characters: [2]CharacterInfo{
+				{FilePath: "one.yaml", Checksum: 1},
+				{FilePath: "two.yaml", Checksum: 2},
+			},
+			Stage: "stage",
+			Seed:  7,
+			Rules: gameplay.DefaultRules(),
+		},
+	}
+	for range 100000 {
+		r.Record([2]input.GameInput{input.NoInput, input.Left})
+	}
+	for i := range 300 {
+		r.Record([2]input.GameInput{input.GameInput(i), input.GameInput(i / 2)})
+	}
+	for range 70000 {
+		r.Record([2]input.GameInput{input.Down | input.Right | input.A, input.NoInput})
+	}
+	return r
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	r := testReplay()
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a run is a few bytes however long it is
+	if len(data) > 4096 {
+		t.Errorf("encoded %d frames in %d bytes, the runs aren't compressed", len(r.Inputs), len(data))
+	}
+
+	var decoded Replay
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Header, r.Header) {
+		t.Errorf("header changed: got %+v, want %+v", decoded.Header, r.Header)
+	}
+	if !reflect.DeepEqual(decoded.Inputs, r.Inputs) {
+		t.Error("inputs changed")
+	}
+
+	path := filepath.Join(t.TempDir(), "match"+FileExtension)
+	if err := Save(path, r); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, r) {
+		t.Error("the loaded replay differs from the saved one")
+	}
+}
+
+func TestEncodingRejectsBrokenFiles(t *testing.T) {
+	valid, err := testReplay().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := func(change func(data []byte) []byte) []byte {
+		return change(append([]byte{}, valid...))
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrNotReplay},
+		{"bad magic", edit(func(data []byte) []byte { data[0] = 'X'; return data }), ErrNotReplay},
+		{"wrong version", edit(func(data []byte) []byte {
+			binary.LittleEndian.PutUint16(data[len(magic):], FormatVersion+1)
+			return data
+		}), ErrIncompatible},
+		{"truncated header", valid[:len(magic)+4], ErrNotReplay},
+		{"truncated run", valid[:len(valid)-1], ErrNotReplay},
+		{"missing runs", valid[:len(valid)-3], ErrNotReplay},
+		{"trailing data", edit(func(data []byte) []byte { return append(data, 0) }), ErrNotReplay},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var r Replay
+			if err := r.UnmarshalBinary(c.data); !errors.Is(err, c.want) {
+				t.Fatalf("got %v, want %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestNewGame(t *testing.T) {
+	game := newTestGame(t)
+	r := New(game, "stage")
+	for i := range 600 {
+		inputs := [2]input.GameInput{input.Right, input.Left}
+		if i%20 == 0 {
+			inputs = [2]input.GameInput{input.A, input.B}
+		}
+		r.Record(inputs)
+		game.Update(inputs)
+	}
+
+	replayed, err := r.NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, inputs := range r.Inputs {
+		replayed.Update(inputs)
+	}
+	recorded, played := game.Save(), replayed.Save()
+	want, err := recorded.Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := played.Checksum(); err != nil || got != want {
+		t.Fatalf("replayed match ended at %016x, the recorded one at %016x (%v)", got, want, err)
+	}
+
+	changed := *r
+	changed.Header.Characters[1].Checksum++
+	if _, err := changed.NewGame(); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("changed character file: got %v, want %v", err, ErrIncompatible)
+	}
+
+	changed = *r
+	changed.Header.EngineVersion = "0.0.0"
+	if _, err := changed.NewGame(); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("other engine version: got %v, want %v", err, ErrIncompatible)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fgengine/constants"
 	"fgengine/gameplay"
 	"fgengine/graphics"
+	"fgengine/hud"
 	"fgengine/input"
+	"fgengine/render"
+	"fgengine/replay"
 	"fgengine/stage"
 	"fgengine/types"
 	"fmt"
@@ -14,9 +17,17 @@ import (
 
 	"github.com/ebitengine/debugui"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
+// SyncTestDistance runs matches in sync test mode when above 0, every frame the last SyncTestDistance frames are
+// simulated again and a desync is printed
+var SyncTestDistance int
+
+// solidColorStage is the only stage for now, replays save its name
+const solidColorStage = "solid color"
+
 func MakeGameplayScene() Scene {
 	playerOne, err := character.LoadCharacter("PlaceHolder", 1)
 	if err != nil {
@@ -28,51 +39,185 @@ func MakeGameplayScene() Scene {
 		panic(err)
 	}
 
+	// like the layout, a broken rules file falls back to the defaults
+	rules, err := gameplay.LoadRules(gameplay.DefaultRulesPath)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	gameplayScene := newGameplayScene(gameplay.GameState{
+		Characters: [2]*character.Character{
+			playerOne,
+			playerTwo,
+		},
+		Rules: rules,
+	})
+	gameplayScene.recording = replay.New(&gameplayScene.gamestate, solidColorStage)
+	if SyncTestDistance > 0 {
+		gameplayScene.syncTest = gameplay.NewSyncTest(&gameplayScene.gamestate, SyncTestDistance)
+	}
+	return gameplayScene
+}
+
+// newGameplayScene shows a game state, the replay scene uses it without the recording and sync test of a match
+func newGameplayScene(gamestate gameplay.GameState) *GameplayScene {
 	camera := graphics.NewCamera()
 	camera.WorldBoundsLock = true
 
+	// a broken layout file shouldn't stop the match, the defaults are used instead
+	layout, err := hud.LoadLayout(hud.DefaultLayoutPath)
+	if err != nil {
+		fmt.Println(err)
+	}
+
 	return &GameplayScene{
-		camera: camera,
-		stage:  stage.NewSolidColorStage(constants.StageColor),
-		gamestate: gameplay.GameState{
-			Characters: [2]*character.Character{
-				playerOne,
-				playerTwo,
-			}}}
+		camera:    camera,
+		stage:     stage.NewSolidColorStage(constants.StageColor),
+		hud:       hud.New(layout),
+		gamestate: gamestate,
+	}
 }
 
 type GameplayScene struct {
 	camera    *graphics.Camera
 	stage     *stage.Stage
 	gamestate gameplay.GameState
+	syncTest  *gameplay.SyncTest // nil unless SyncTestDistance is set
+	recording *replay.Replay     // inputs are recorded by the scene manager, it knows which ones are final in netplay
+	hud       *hud.HUD
 	debugui   debugui.DebugUI
+
+	flashCameraOwner int // player the camera script of the last super flash zooms on
+
+	layers [constants.LayerCount]*ebiten.Image
 }
 
+// layerDrawOrder composes the layers back to front, the HUD goes over everything
+var layerDrawOrder = [constants.LayerCount]int{constants.LayerBG, constants.LayerPlayer, constants.LayerEffects, constants.LayerHUD}
+
+const (
+	superFlashZoom    = 1.4 // camera scaling reached on the attacker during a super flash
+	superFlashEaseIn  = 6   // frames the camera takes to zoom in
+	superFlashEaseOut = 6   // frames the camera takes to zoom back out after the flash
+
+	roundCallFrames = 60 // "ROUND n" is shown for the start of the intro, then "READY"
+	fightCallFrames = 40 // "FIGHT!" is shown once players can act
+)
+
+var superFlashShade = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+
 func (g *GameplayScene) Update(inputs [2]input.GameInput) SceneStatus {
-	g.gamestate.Update(inputs)
+	if g.syncTest != nil {
+		// the match keeps going after a desync, only the first one is reported
+		if err := g.syncTest.Update(inputs); err != nil {
+			fmt.Println(err)
+			g.syncTest = nil
+		}
+	} else {
+		g.gamestate.Update(inputs)
+	}
+	g.updateView()
+	if g.gamestate.Match.Over() {
+		return SceneMatchEnd
+	}
+	return SceneDontChange
+}
+
+// updateView follows the game state after it changed, with the HUD and the camera
+func (g *GameplayScene) updateView() {
+	g.hud.Update(&g.gamestate)
+	g.startSuperFlashCamera()
 	g.updateCamera()
 	g.updateDebugUI()
-	return SceneDontChange
 }
 
 func (g *GameplayScene) Draw(screen *ebiten.Image) {
+	background := g.layer(screen, constants.LayerBG)
 	if g.stage != nil {
-		g.stage.Draw(screen, g.camera)
+		g.stage.Draw(background, g.camera)
 	}
 
+	// the stage darkens behind the characters during a super flash
+	if g.gamestate.SuperFlash.Active() {
+		bounds := background.Bounds()
+		vector.FillRect(background, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), superFlashShade, false)
+	}
+
+	players := g.layer(screen, constants.LayerPlayer)
 	for _, char := range g.gamestate.Characters {
 		if char == nil {
 			continue
 		}
-		char.Draw(screen, g.camera)
-		char.DrawBoxes(screen, g.camera)
+		render.DrawCharacter(players, g.camera, char)
+		render.DrawCharacterBoxes(players, g.camera, char)
+	}
+
+	effects := g.layer(screen, constants.LayerEffects)
+	for _, e := range g.gamestate.Entities {
+		render.DrawEntity(effects, g.camera, e)
+		render.DrawEntityBoxes(effects, g.camera, e)
 	}
+	g.drawDebugGuides(effects)
 
-	g.drawDebugGuides(screen)
+	hudLayer := g.layer(screen, constants.LayerHUD)
+	g.hud.Draw(hudLayer, &g.gamestate)
+	g.drawMatchAnnouncement(hudLayer)
+
+	for _, index := range layerDrawOrder {
+		screen.DrawImage(g.layers[index], nil)
+	}
 
 	//g.debugui.Draw(screen)
 }
 
+// layer returns the cleared offscreen image of a layer, it is recreated when the screen size changes
+func (g *GameplayScene) layer(screen *ebiten.Image, index int) *ebiten.Image {
+	img := g.layers[index]
+	if img == nil || img.Bounds() != screen.Bounds() {
+		img = ebiten.NewImageWithOptions(screen.Bounds(), nil)
+		g.layers[index] = img
+	}
+	img.Clear()
+	return img
+}
+
+// drawMatchAnnouncement prints the round calls and results in the middle of the screen
+func (g *GameplayScene) drawMatchAnnouncement(screen *ebiten.Image) {
+	text := matchAnnouncement(g.gamestate.Match)
+	if text == "" {
+		return
+	}
+	bounds := screen.Bounds()
+	ebitenutil.DebugPrintAt(screen, text, bounds.Dx()/2-len(text)*3, bounds.Dy()/3)
+}
+
+func matchAnnouncement(match gameplay.Match) string {
+	switch match.Phase {
+	case gameplay.MatchIntro:
+		if match.PhaseFrames < roundCallFrames {
+			return fmt.Sprintf("ROUND %d", match.Round)
+		}
+		return "READY"
+	case gameplay.MatchFight:
+		if match.PhaseFrames < fightCallFrames {
+			return "FIGHT!"
+		}
+	case gameplay.MatchKO, gameplay.MatchRoundEnd:
+		switch match.RoundEnd {
+		case gameplay.RoundKO:
+			return "K.O."
+		case gameplay.RoundDoubleKO:
+			return "DOUBLE K.O."
+		case gameplay.RoundTimeOver:
+			if match.RoundWinner < 0 {
+				return "TIME OVER - DRAW"
+			}
+			return "TIME OVER"
+		}
+	}
+	return ""
+}
+
 func (g *GameplayScene) drawDebugGuides(screen *ebiten.Image) {
 	if g.camera == nil {
 		return
@@ -139,6 +284,27 @@ func (g *GameplayScene) drawDebugGuides(screen *ebiten.Image) {
 	}
 }
 
+// startSuperFlashCamera zooms on the attacker when a super flash starts, the script lasts as long as the flash.
+// A counter super of the opponent takes the camera over.
+func (g *GameplayScene) startSuperFlashCamera() {
+	flash := g.gamestate.SuperFlash
+	if g.camera == nil || !flash.Active() {
+		return
+	}
+	if g.camera.Script != nil && g.flashCameraOwner == flash.Owner {
+		return
+	}
+
+	g.flashCameraOwner = flash.Owner
+	g.camera.PlayScript(graphics.CameraScript{
+		Target:  g.gamestate.Characters[flash.Owner].Position(),
+		Zoom:    superFlashZoom,
+		Frames:  flash.FramesLeft,
+		EaseIn:  superFlashEaseIn,
+		EaseOut: superFlashEaseOut,
+	})
+}
+
 func (g *GameplayScene) updateCamera() {
 	if g.camera == nil {
 		return
@@ -175,6 +341,8 @@ func (g *GameplayScene) updateCamera() {
 func (g *GameplayScene) updateDebugUI() {
 	_, err := g.debugui.Update(func(ctx *debugui.Context) error {
 		ctx.Window("Gameplay Debug", image.Rect(0, 0, 320, 340), func(layout debugui.ContainerLayout) {
+			match := g.gamestate.Match
+			ctx.Text(fmt.Sprintf("Round %d %s timer=%d wins=%d-%d", match.Round, match.Phase, match.Timer, match.Wins[0], match.Wins[1]))
 			ctx.Text("Players")
 
 			for i, char := range g.gamestate.Characters {
@@ -193,10 +361,16 @@ func (g *GameplayScene) updateDebugUI() {
 					frameTimeLeft = sm.AnimPlayer.FrameTimeLeft
 				}
 
-				ctx.Text(fmt.Sprintf("P%d pos=(%.2f, %.2f)", i+1, sm.Position.X, sm.Position.Y))
-				ctx.Text(fmt.Sprintf("P%d vel=(%.2f, %.2f)", i+1, sm.Velocity.X, sm.Velocity.Y))
+				ctx.Text(fmt.Sprintf("P%d pos=(%.2f, %.2f)", i+1, sm.Position.X.Float(), sm.Position.Y.Float()))
+				ctx.Text(fmt.Sprintf("P%d vel=(%.2f, %.2f)", i+1, sm.Velocity.X.Float(), sm.Velocity.Y.Float()))
 				ctx.Text(fmt.Sprintf("P%d facing=%v", i+1, sm.IsFacingLeft))
-				ctx.Text(fmt.Sprintf("P%d anim=%s frame=%d t=%d", i+1, animName, frameIndex, frameTimeLeft))
+				ctx.Text(fmt.Sprintf("P%d hp=%d reaction=%s hitstun=%d blockstun=%d", i+1, sm.HP, sm.Reaction, sm.Hitstun, sm.Blockstun))
+				ctx.Text(fmt.Sprintf("P%d meter=%d/%d", i+1, g.gamestate.Meter[i], char.MaxMeter()))
+				combo, dropped := g.gamestate.Combos[i], g.gamestate.DroppedCombos[i]
+				ctx.Text(fmt.Sprintf("P%d combo hits=%d damage=%d last dropped hits=%d damage=%d", i+1, combo.Hits, combo.Damage, dropped.Hits, dropped.Damage))
+				knockdown := g.gamestate.Knockdowns[i]
+				ctx.Text(fmt.Sprintf("P%d knockdown hard=%v duration=%d actionable in=%d", i+1, knockdown.Hard, knockdown.Duration, g.gamestate.FramesUntilActionable(i)))
+				ctx.Text(fmt.Sprintf("P%d anim=%s frame=%d t=%d phase=%s", i+1, animName, frameIndex, frameTimeLeft, sm.AnimPlayer.ActivePhase()))
 			}
 
 			if g.camera != nil {
@@ -0,0 +1,43 @@
+package scene
+
+import (
+	"fgengine/gameplay"
+	"fgengine/input"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+type MatchEndScene struct {
+	match      gameplay.Match
+	prevInputs [2]input.GameInput
+}
+
+// MakeMatchEndScene shows the result of the match, inputs are the ones of the frame it ended on so a held A doesn't skip it
+func MakeMatchEndScene(match gameplay.Match, inputs [2]input.GameInput) Scene {
+	return &MatchEndScene{match: match, prevInputs: inputs}
+}
+
+func (m *MatchEndScene) Update(inputs [2]input.GameInput) SceneStatus {
+	defer func() { m.prevInputs = inputs }()
+
+	// either player can go back to the menu
+	for i := range inputs {
+		if input.JustPressed(inputs[i], m.prevInputs[i], input.A) {
+			return Scene1
+		}
+	}
+	return SceneDontChange
+}
+
+func (m *MatchEndScene) Draw(screen *ebiten.Image) {
+	result := "DRAW"
+	if m.match.Winner >= 0 {
+		result = fmt.Sprintf("PLAYER %d WINS", m.match.Winner+1)
+	}
+
+	ebitenutil.DebugPrintAt(screen, result, 75, 40)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Rounds %d - %d", m.match.Wins[0], m.match.Wins[1]), 75, 70)
+	ebitenutil.DebugPrintAt(screen, "Press A to return to the menu", 75, 120)
+}
@@ -0,0 +1,126 @@
+package scene
+
+import (
+	"fgengine/gameplay"
+	"fgengine/input"
+	"fgengine/replay"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const keyframeInterval = 60 // frames between two snapshots kept for rewinding
+
+// replaySpeeds are in quarters of a frame per update, normal speed is replaySpeeds[normalReplaySpeed]
+var replaySpeeds = []int{1, 2, 4, 8, 16}
+
+const normalReplaySpeed = 2
+
+// ReplayScene plays a replay back. A pauses, left and right step one frame while paused, holding left rewinds,
+// up and down change the speed and D goes back to the menu.
+type ReplayScene struct {
+	view      *GameplayScene
+	replay    *replay.Replay
+	frame     int                 // replay inputs already simulated
+	keyframes []gameplay.Snapshot // keyframes[i] is the state before frame i*keyframeInterval
+
+	paused     bool
+	speed      int // index in replaySpeeds
+	speedCarry int // quarters of a frame not simulated yet
+	prevInputs input.GameInput
+}
+
+func MakeReplayScene(r *replay.Replay) (Scene, error) {
+	game, err := r.NewGame()
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayScene{
+		view:   newGameplayScene(*game),
+		replay: r,
+		speed:  normalReplaySpeed,
+	}, nil
+}
+
+func (r *ReplayScene) Update(inputs [2]input.GameInput) SceneStatus {
+	// either player controls the playback
+	current := inputs[0] | inputs[1]
+	prev := r.prevInputs
+	defer func() { r.prevInputs = current }()
+
+	if input.JustPressed(current, prev, input.D) {
+		return Scene1
+	}
+	if input.JustPressed(current, prev, input.A) {
+		r.paused = !r.paused
+	}
+	if input.JustPressed(current, prev, input.Up) {
+		r.speed = min(r.speed+1, len(replaySpeeds)-1)
+	}
+	if input.JustPressed(current, prev, input.Down) {
+		r.speed = max(r.speed-1, 0)
+	}
+
+	switch {
+	case r.paused:
+		if input.JustPressed(current, prev, input.Right) {
+			r.stepForward()
+		}
+		if input.JustPressed(current, prev, input.Left) {
+			r.seek(r.frame - 1)
+		}
+	default:
+		r.speedCarry += replaySpeeds[r.speed]
+		frames := r.speedCarry / 4
+		r.speedCarry %= 4
+		if current.IsPressed(input.Left) {
+			r.seek(r.frame - frames)
+			break
+		}
+		for range frames {
+			r.stepForward()
+		}
+	}
+
+	r.view.updateView()
+	return SceneDontChange
+}
+
+// stepForward simulates the next frame of the replay, the playback pauses at the end
+func (r *ReplayScene) stepForward() {
+	if r.frame >= len(r.replay.Inputs) {
+		r.paused = true
+		return
+	}
+	if r.frame%keyframeInterval == 0 && r.frame/keyframeInterval == len(r.keyframes) {
+		r.keyframes = append(r.keyframes, r.view.gamestate.Save())
+	}
+	r.view.gamestate.Update(r.replay.Inputs[r.frame])
+	r.frame++
+}
+
+// seek goes back to an earlier frame, from the keyframe before it
+func (r *ReplayScene) seek(target int) {
+	target = max(target, 0)
+	if target >= r.frame {
+		return
+	}
+	keyframe := target / keyframeInterval
+	r.view.gamestate.Load(&r.keyframes[keyframe])
+	r.frame = keyframe * keyframeInterval
+	for r.frame < target {
+		r.stepForward()
+	}
+}
+
+func (r *ReplayScene) Draw(screen *ebiten.Image) {
+	r.view.Draw(screen)
+
+	status := fmt.Sprintf("REPLAY %d/%d x%.2f", r.frame, len(r.replay.Inputs), float64(replaySpeeds[r.speed])/4)
+	if r.paused {
+		status += " PAUSED"
+	}
+	ebitenutil.DebugPrintAt(screen, status, 10, screen.Bounds().Dy()-30)
+	ebitenutil.DebugPrintAt(screen, "A pause  <- -> step/rewind  up/down speed  D exit", 10, screen.Bounds().Dy()-16)
+}
@@ -2,7 +2,14 @@ package scene
 
 import (
 	"fgengine/constants"
+	"fgengine/controller"
 	"fgengine/input"
+	"fgengine/netplay"
+	"fgengine/replay"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -19,16 +26,24 @@ const (
 	Scene1
 	Scene2
 	SceneController
+	SceneMatchEnd // constants.SceneMatchEnd, shows the result of the gameplay scene that returned it
 )
 
 type SceneManager struct {
 	currentScene Scene
 	// helper var to not trigger commands in scenes other than the active one
 	waitNeutral bool
+	// when set the gameplay scene gets both players' inputs from it, it's closed once the match scene is left
+	session *netplay.Session
 }
 
 func (sm *SceneManager) Update() error {
-	polledInputs := input.UpdateGamepads()
+	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
+		sm.endSession()
+		return ebiten.Termination
+	}
+
+	polledInputs := controller.UpdateGamepads()
 	activeInputs := polledInputs
 
 	// Prevent button carry-over between scenes by waiting for full release.
@@ -40,7 +55,35 @@ func (sm *SceneManager) Update() error {
 		}
 	}
 
+	if gameplayScene, ok := sm.currentScene.(*GameplayScene); ok && sm.session != nil {
+		// every local device controls the local player
+		inputs, advance, err := sm.session.AdvanceFrame(&gameplayScene.gamestate, polledInputs[0]|polledInputs[1])
+		if err != nil {
+			fmt.Println(err)
+			sm.endSession()
+			sm.currentScene = MakeMainMenuScene()
+			sm.waitNeutral = true
+			return nil
+		}
+		for _, confirmed := range sm.session.ConfirmedInputs() {
+			gameplayScene.recording.Record(confirmed)
+		}
+		if !advance {
+			return nil
+		}
+		activeInputs = inputs
+	} else if ok && gameplayScene.recording != nil {
+		gameplayScene.recording.Record(activeInputs)
+	}
+
 	sceneSignal := sm.currentScene.Update(activeInputs)
+	if sm.session != nil && !sm.session.Confirmed() {
+		// the match could end on a misprediction, the scene asks again on the next frames
+		sceneSignal = SceneDontChange
+	}
+	if sceneSignal != SceneDontChange {
+		sm.endSession()
+	}
 	switch sceneSignal {
 	case Scene1:
 		sm.currentScene = MakeMainMenuScene()
@@ -51,14 +94,44 @@ func (sm *SceneManager) Update() error {
 	case SceneController:
 		sm.currentScene = MakeControllerScene()
 		sm.waitNeutral = true
+	case SceneMatchEnd:
+		if gameplayScene, ok := sm.currentScene.(*GameplayScene); ok {
+			saveReplay(gameplayScene.recording)
+			sm.currentScene = MakeMatchEndScene(gameplayScene.gamestate.Match, activeInputs)
+			sm.waitNeutral = true
+		}
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
-		return ebiten.Termination
-	}
 	return nil
 }
 
+func (sm *SceneManager) endSession() {
+	if sm.session == nil {
+		return
+	}
+	if err := sm.session.Close(); err != nil {
+		fmt.Println(err)
+	}
+	sm.session = nil
+}
+
+// saveReplay writes the replay of a finished match in replay.DefaultDir, a failure is only printed
+func saveReplay(r *replay.Replay) {
+	if r == nil {
+		return
+	}
+	if err := os.MkdirAll(replay.DefaultDir, 0o755); err != nil {
+		fmt.Println(err)
+		return
+	}
+	path := filepath.Join(replay.DefaultDir, time.Now().Format("20060102-150405")+replay.FileExtension)
+	if err := replay.Save(path, r); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("replay saved to", path)
+}
+
 func (sm *SceneManager) Draw(screen *ebiten.Image) {
 	sm.currentScene.Draw(screen)
 }
@@ -73,3 +146,23 @@ func NewSceneManager() *SceneManager {
 		waitNeutral:  true,
 	}
 }
+
+// NewReplaySceneManager starts on the playback of a replay, leaving it goes to the main menu
+func NewReplaySceneManager(r *replay.Replay) (*SceneManager, error) {
+	replayScene, err := MakeReplayScene(r)
+	if err != nil {
+		return nil, err
+	}
+	return &SceneManager{
+		currentScene: replayScene,
+		waitNeutral:  true,
+	}, nil
+}
+
+// NewNetplaySceneManager starts straight into a match against the remote player of the session
+func NewNetplaySceneManager(session *netplay.Session) *SceneManager {
+	return &SceneManager{
+		currentScene: MakeGameplayScene(),
+		session:      session,
+	}
+}
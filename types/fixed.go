@@ -0,0 +1,154 @@
+package types
+
+import "math"
+
+// Fixed is a fixed point number with FixedShift fractional bits, the simulation uses it instead of float64 so every
+// machine computes the exact same frames. Floats are only for authored data (converted once when used) and rendering.
+type Fixed int64
+
+const (
+	FixedShift       = 16
+	FixedOne   Fixed = 1 << FixedShift
+)
+
+func IntToFixed(i int) Fixed {
+	return Fixed(i) << FixedShift
+}
+
+// FloatToFixed rounds to the nearest fixed value, scaling by a power of two is exact so the result is the same everywhere.
+func FloatToFixed(f float64) Fixed {
+	return Fixed(math.Round(f * float64(FixedOne)))
+}
+
+func (f Fixed) Float() float64 {
+	return float64(f) / float64(FixedOne)
+}
+
+// Int truncates towards negative infinity
+func (f Fixed) Int() int {
+	return int(f >> FixedShift)
+}
+
+// Mul rounds to the nearest value with ties away from zero, so a negated operand gives the negated result and
+// mirrored motion stays mirrored
+func (f Fixed) Mul(o Fixed) Fixed {
+	product := f * o
+	if product < 0 {
+		return -((-product + FixedOne/2) >> FixedShift)
+	}
+	return (product + FixedOne/2) >> FixedShift
+}
+
+// Div truncates towards zero, so negating an operand negates the result
+func (f Fixed) Div(o Fixed) Fixed {
+	return (f << FixedShift) / o
+}
+
+func (f Fixed) Abs() Fixed {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// Sqrt uses integer Newton iterations, negative values return 0
+func (f Fixed) Sqrt() Fixed {
+	if f <= 0 {
+		return 0
+	}
+	// sqrt(f * one) keeps the result scaled by one
+	n := uint64(f) << FixedShift
+	x := n
+	y := (x + 1) / 2
+	for y < x {
+		x = y
+		y = (x + n/x) / 2
+	}
+	return Fixed(x)
+}
+
+type FixedVector2 struct {
+	X Fixed
+	Y Fixed
+}
+
+type FixedRect struct {
+	X Fixed
+	Y Fixed
+	W Fixed
+	H Fixed
+}
+
+func (v Vector2) Fixed() FixedVector2 {
+	return FixedVector2{X: FloatToFixed(v.X), Y: FloatToFixed(v.Y)}
+}
+
+func (v FixedVector2) Float() Vector2 {
+	return Vector2{X: v.X.Float(), Y: v.Y.Float()}
+}
+
+func (v FixedVector2) Add(o FixedVector2) FixedVector2 {
+	return FixedVector2{v.X + o.X, v.Y + o.Y}
+}
+
+func (v FixedVector2) Sub(o FixedVector2) FixedVector2 {
+	return FixedVector2{v.X - o.X, v.Y - o.Y}
+}
+
+func (v FixedVector2) Mul(s Fixed) FixedVector2 {
+	return FixedVector2{v.X.Mul(s), v.Y.Mul(s)}
+}
+
+func (v FixedVector2) Length() Fixed {
+	return (v.X.Mul(v.X) + v.Y.Mul(v.Y)).Sqrt()
+}
+
+func (r Rect) Fixed() FixedRect {
+	return FixedRect{X: FloatToFixed(r.X), Y: FloatToFixed(r.Y), W: FloatToFixed(r.W), H: FloatToFixed(r.H)}
+}
+
+func (r FixedRect) Float() Rect {
+	return Rect{X: r.X.Float(), Y: r.Y.Float(), W: r.W.Float(), H: r.H.Float()}
+}
+
+func (r FixedRect) Right() Fixed {
+	return r.X + r.W
+}
+
+func (r FixedRect) Bottom() Fixed {
+	return r.Y + r.H
+}
+
+// Center returns the center coordinates of the rectangle
+func (r FixedRect) Center() FixedVector2 {
+	return FixedVector2{X: r.X + r.W/2, Y: r.Y + r.H/2}
+}
+
+func (r FixedRect) Contains(p FixedVector2) bool {
+	return p.X >= r.X && p.X < r.Right() && p.Y >= r.Y && p.Y < r.Bottom()
+}
+
+// Regular AABB collision check, does not consider edges as overlapping (exclusive)
+func (r FixedRect) IsOverlapping(other FixedRect) bool {
+	return r.X < other.Right() &&
+		r.Right() > other.X &&
+		r.Y < other.Bottom() &&
+		r.Bottom() > other.Y
+}
+
+// Intersection returns the overlapping area of two rectangles, zero sized if they don't overlap
+func (r FixedRect) Intersection(other FixedRect) FixedRect {
+	x := max(r.X, other.X)
+	y := max(r.Y, other.Y)
+	right := min(r.Right(), other.Right())
+	bottom := min(r.Bottom(), other.Bottom())
+	if right <= x || bottom <= y {
+		return FixedRect{X: x, Y: y}
+	}
+	return FixedRect{X: x, Y: y, W: right - x, H: bottom - y}
+}
+
+// Expand grows the rectangle by amount on every side
+func (r FixedRect) Expand(amount Fixed) FixedRect {
+	return FixedRect{X: r.X - amount, Y: r.Y - amount, W: r.W + 2*amount, H: r.H + 2*amount}
+}
@@ -0,0 +1,59 @@
+package types
+
+import "testing"
+
+func TestFixedMul(t *testing.T) {
+	half := FixedOne / 2
+	cases := []struct {
+		f, o, want Fixed
+	}{
+		{IntToFixed(3), IntToFixed(2), IntToFixed(6)},
+		{IntToFixed(-3), IntToFixed(2), IntToFixed(-6)},
+		{IntToFixed(-3), IntToFixed(-2), IntToFixed(6)},
+		{half, 3, 2},   // 1.5 rounds away from zero
+		{-half, 3, -2}, // and so does -1.5
+		{half, 1, 1},
+		{-half, 1, -1},
+		{FixedOne / 4, 3, 1}, // 0.75
+		{-FixedOne / 4, 3, -1},
+		{FixedOne / 4, 1, 0}, // 0.25
+		{-FixedOne / 4, 1, 0},
+		{FloatToFixed(0.9), FloatToFixed(-1.3), -FloatToFixed(0.9).Mul(FloatToFixed(1.3))},
+	}
+	for _, c := range cases {
+		if got := c.f.Mul(c.o); got != c.want {
+			t.Errorf("%d * %d = %d, want %d", c.f, c.o, got, c.want)
+		}
+		if got := c.o.Mul(c.f); got != c.want {
+			t.Errorf("%d * %d = %d, want %d", c.o, c.f, got, c.want)
+		}
+	}
+
+	// mirroring a value never changes its size
+	for f := Fixed(-1000); f <= 1000; f++ {
+		o := FloatToFixed(0.85)
+		if f.Mul(o) != -(-f).Mul(o) {
+			t.Fatalf("%d * %d = %d but %d * %d = %d", f, o, f.Mul(o), -f, o, (-f).Mul(o))
+		}
+	}
+}
+
+func TestFixedDiv(t *testing.T) {
+	cases := []struct {
+		f, o, want Fixed
+	}{
+		{IntToFixed(6), IntToFixed(2), IntToFixed(3)},
+		{IntToFixed(-6), IntToFixed(2), IntToFixed(-3)},
+		{IntToFixed(-6), IntToFixed(-2), IntToFixed(3)},
+		{1, IntToFixed(3), 0}, // truncates towards zero
+		{-1, IntToFixed(3), 0},
+		{IntToFixed(1), IntToFixed(3), 21845},
+		{IntToFixed(-1), IntToFixed(3), -21845},
+		{IntToFixed(1), IntToFixed(-3), -21845},
+	}
+	for _, c := range cases {
+		if got := c.f.Div(c.o); got != c.want {
+			t.Errorf("%d / %d = %d, want %d", c.f, c.o, got, c.want)
+		}
+	}
+}
@@ -20,6 +20,8 @@ const (
 	Collision BoxType = iota
 	Hit
 	Hurt
+	Throw     // grabs a Throwable box
+	Throwable // where the character can be grabbed, the collision box is used when a frame has none
 )
 
 func (b BoxType) String() string {
@@ -30,6 +32,10 @@ func (b BoxType) String() string {
 		return "Hit"
 	case Hurt:
 		return "Hurt"
+	case Throw:
+		return "Throw"
+	case Throwable:
+		return "Throwable"
 	default:
 		return "Unknown"
 	}